@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// domainError maps a models error to its problem. The detail defaults to the error message.
+// A non-empty field reports the error as a rejection of that request field with the field code.
+type domainError struct {
+	err       error
+	status    int
+	code      string
+	detail    string
+	field     string
+	fieldCode string
+}
+
+// domainErrors maps the errors of the models package to problems. Errors are matched in order with
+// errors.Is, so errors wrapping another one, such as ErrProductCategoryNotFound, come first.
+var domainErrors = []domainError{
+	{err: models.ErrProductCodeAlreadyExists, status: http.StatusConflict, code: "product_code_already_exists"},
+	{err: models.ErrProductCategoryNotFound, status: http.StatusBadRequest, code: CodeValidationFailed, detail: "category not found", field: "category", fieldCode: FieldNotFound},
+	{err: models.ErrProductNotFound, status: http.StatusNotFound, code: "product_not_found"},
+	{err: models.ErrVariantSKUAlreadyExists, status: http.StatusConflict, code: "variant_sku_already_exists"},
+	{err: models.ErrVariantSKUOfAnotherProduct, status: http.StatusConflict, code: "variant_sku_of_another_product"},
+	{err: models.ErrVariantNotFound, status: http.StatusNotFound, code: "variant_not_found"},
+	{err: models.ErrInsufficientStock, status: http.StatusConflict, code: "insufficient_stock"},
+	{err: models.ErrStockVersionConflict, status: http.StatusConflict, code: "stock_version_conflict"},
+	{err: models.ErrReservationNotFound, status: http.StatusNotFound, code: "reservation_not_found"},
+	{err: models.ErrReservationNotHeld, status: http.StatusConflict, code: "reservation_not_held"},
+	{err: models.ErrReservationExpired, status: http.StatusGone, code: "reservation_expired"},
+	{err: models.ErrCategoryCodeAlreadyExists, status: http.StatusConflict, code: "category_code_already_exists"},
+	{err: models.ErrCategoryInUse, status: http.StatusConflict, code: "category_in_use"},
+	{err: models.ErrCategoryHasChildren, status: http.StatusConflict, code: "category_has_children"},
+	{err: models.ErrParentCategoryNotFound, status: http.StatusBadRequest, code: CodeValidationFailed, field: "parent", fieldCode: FieldNotFound},
+	{err: models.ErrCategoryCycle, status: http.StatusBadRequest, code: CodeValidationFailed, field: "parent", fieldCode: FieldInvalid},
+	{err: models.ErrReassignCategoryNotFound, status: http.StatusBadRequest, code: CodeInvalidQueryParameter, field: "reassign_to", fieldCode: FieldNotFound},
+	{err: models.ErrCategoryNotFound, status: http.StatusNotFound, code: "category_not_found"},
+	{err: models.ErrMarketNotFound, status: http.StatusBadRequest, code: CodeInvalidQueryParameter, detail: "unknown market", field: "market", fieldCode: FieldNotFound},
+	{err: models.ErrUnsupportedConversion, status: http.StatusBadRequest, code: CodeInvalidQueryParameter, detail: "unsupported currency", field: "currency", fieldCode: FieldInvalid},
+	{err: models.ErrExchangeRateNotFound, status: http.StatusNotFound, code: "exchange_rate_not_found"},
+}
+
+// DomainErrorResponse writes the problem of an error returned by the models package. Known domain errors
+// map to their status and code, exceeded deadlines such as a query outliving the request's query timeout
+// to HTTP 504, and any other error to HTTP 500 with the fallback detail.
+func DomainErrorResponse(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	for _, known := range domainErrors {
+		if !errors.Is(err, known.err) {
+			continue
+		}
+
+		problem := Problem{Status: known.status, Code: known.code, Detail: known.detail}
+		if problem.Detail == "" {
+			problem.Detail = known.err.Error()
+		}
+
+		var inUse *models.CategoryInUseError
+		if errors.As(err, &inUse) && inUse.ProductCount > 0 {
+			problem.Detail = fmt.Sprintf("category still has %d products", inUse.ProductCount)
+			problem.ProductCount = &inUse.ProductCount
+		}
+
+		if known.field != "" {
+			problem.Errors = []FieldError{{Field: known.field, Code: known.fieldCode, Message: problem.Detail}}
+		}
+
+		ProblemResponse(w, r, problem)
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		ErrorResponse(w, r, http.StatusGatewayTimeout, CodeTimeout, "request timed out")
+		return
+	}
+
+	ErrorResponse(w, r, http.StatusInternalServerError, CodeInternal, fallback)
+}
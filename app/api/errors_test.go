@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainErrorResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+		field  *FieldError
+		// productCount is the expected product_count extension member, zero when it is omitted.
+		productCount int64
+	}{
+		{
+			name:   "missing product",
+			err:    fmt.Errorf("get product failed: %w", models.ErrProductNotFound),
+			status: http.StatusNotFound,
+			code:   "product_not_found",
+			detail: "product not found",
+		},
+		{
+			name:   "conflicting product code",
+			err:    models.ErrProductCodeAlreadyExists,
+			status: http.StatusConflict,
+			code:   "product_code_already_exists",
+			detail: models.ErrProductCodeAlreadyExists.Error(),
+		},
+		{
+			name:   "unknown category of a product",
+			err:    models.ErrProductCategoryNotFound,
+			status: http.StatusBadRequest,
+			code:   CodeValidationFailed,
+			detail: "category not found",
+			field:  &FieldError{Field: "category", Code: FieldNotFound, Message: "category not found"},
+		},
+		{
+			name:   "missing category",
+			err:    models.ErrCategoryNotFound,
+			status: http.StatusNotFound,
+			code:   "category_not_found",
+			detail: models.ErrCategoryNotFound.Error(),
+		},
+		{
+			name:         "category in use",
+			err:          &models.CategoryInUseError{ProductCount: 3},
+			status:       http.StatusConflict,
+			code:         "category_in_use",
+			detail:       "category still has 3 products",
+			productCount: 3,
+		},
+		{
+			name:   "category in use by an unknown number of products",
+			err:    &models.CategoryInUseError{},
+			status: http.StatusConflict,
+			code:   "category_in_use",
+			detail: models.ErrCategoryInUse.Error(),
+		},
+		{
+			name:   "unsupported currency",
+			err:    models.ErrUnsupportedConversion,
+			status: http.StatusBadRequest,
+			code:   CodeInvalidQueryParameter,
+			detail: "unsupported currency",
+			field:  &FieldError{Field: "currency", Code: FieldInvalid, Message: "unsupported currency"},
+		},
+		{
+			name:   "missing exchange rate",
+			err:    models.ErrExchangeRateNotFound,
+			status: http.StatusNotFound,
+			code:   "exchange_rate_not_found",
+			detail: models.ErrExchangeRateNotFound.Error(),
+		},
+		{
+			name:   "exceeded deadline",
+			err:    fmt.Errorf("list products failed: %w", context.DeadlineExceeded),
+			status: http.StatusGatewayTimeout,
+			code:   CodeTimeout,
+			detail: "request timed out",
+		},
+		{
+			name:   "other errors",
+			err:    errors.New("db failed"),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+			detail: "failed to fetch products",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+			DomainErrorResponse(recorder, request, tt.err, "failed to fetch products")
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+
+			var problem Problem
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, tt.detail, problem.Detail)
+			if tt.productCount == 0 {
+				assert.Nil(t, problem.ProductCount)
+			} else if assert.NotNil(t, problem.ProductCount) {
+				assert.Equal(t, tt.productCount, *problem.ProductCount)
+			}
+			if tt.field == nil {
+				assert.Empty(t, problem.Errors)
+			} else {
+				assert.Equal(t, []FieldError{*tt.field}, problem.Errors)
+			}
+		})
+	}
+}
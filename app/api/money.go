@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MediaTypeV2 is the Accept media type selecting exact money objects in responses.
+const MediaTypeV2 = "application/vnd.catalog.v2+json"
+
+// MoneyFormat selects the JSON representation of Money.
+type MoneyFormat int
+
+const (
+	// MoneyFormatFloat writes the amount as a JSON number, which may be inexact. It is the default.
+	MoneyFormatFloat MoneyFormat = iota
+	// MoneyFormatString writes the exact amount as a JSON string such as "10.99".
+	MoneyFormatString
+	// MoneyFormatObject writes an object holding the exact amount as a string and the currency code.
+	MoneyFormatObject
+)
+
+// Money is a monetary amount whose JSON representation is chosen by Format.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+	Format   MoneyFormat
+}
+
+// moneyObject is the JSON shape of MoneyFormatObject.
+type moneyObject struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// NewMoney creates a Money value in the given format.
+func NewMoney(amount decimal.Decimal, currency string, format MoneyFormat) Money {
+	return Money{Amount: amount, Currency: currency, Format: format}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {
+	switch m.Format {
+	case MoneyFormatString:
+		return json.Marshal(m.Amount.StringFixed(2))
+	case MoneyFormatObject:
+		return json.Marshal(moneyObject{Amount: m.Amount.StringFixed(2), Currency: m.Currency})
+	default:
+		return json.Marshal(m.Amount.InexactFloat64())
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler and accepts every format written by MarshalJSON.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var object moneyObject
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+
+		amount, err := decimal.NewFromString(object.Amount)
+		if err != nil {
+			return err
+		}
+
+		*m = Money{Amount: amount, Currency: object.Currency, Format: MoneyFormatObject}
+		return nil
+	}
+
+	format := MoneyFormatFloat
+	if strings.HasPrefix(trimmed, `"`) {
+		format = MoneyFormatString
+	}
+
+	var amount decimal.Decimal
+	if err := amount.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	*m = Money{Amount: amount, Format: format}
+	return nil
+}
+
+// MoneyFormatFromRequest negotiates the money format of a response.
+// The money query parameter (float, string or object) takes precedence over the Accept header,
+// where MediaTypeV2 selects MoneyFormatObject. Without either, MoneyFormatFloat keeps the legacy shape.
+// When the Accept header is consulted the response varies on it, and MediaTypeV2 becomes its content type.
+// It reports false for an unknown money query parameter.
+func MoneyFormatFromRequest(w http.ResponseWriter, r *http.Request) (MoneyFormat, bool) {
+	switch r.URL.Query().Get("money") {
+	case "":
+	case "float":
+		return MoneyFormatFloat, true
+	case "string":
+		return MoneyFormatString, true
+	case "object":
+		return MoneyFormatObject, true
+	default:
+		return MoneyFormatFloat, false
+	}
+
+	w.Header().Add("Vary", "Accept")
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == MediaTypeV2 {
+			w.Header().Set("Content-Type", MediaTypeV2)
+			return MoneyFormatObject, true
+		}
+	}
+
+	return MoneyFormatFloat, true
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	amount := decimal.RequireFromString("10.1")
+	cases := map[string]struct {
+		format MoneyFormat
+		want   string
+	}{
+		"float":  {format: MoneyFormatFloat, want: `10.1`},
+		"string": {format: MoneyFormatString, want: `"10.10"`},
+		"object": {format: MoneyFormatObject, want: `{"amount":"10.10","currency":"EUR"}`},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(NewMoney(amount, "EUR", tc.format))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(data))
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		data     string
+		format   MoneyFormat
+		currency string
+	}{
+		"float":  {data: `0.3`, format: MoneyFormatFloat},
+		"string": {data: `"0.30"`, format: MoneyFormatString},
+		"object": {data: `{"amount":"0.30","currency":"GBP"}`, format: MoneyFormatObject, currency: "GBP"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var money Money
+			require.NoError(t, json.Unmarshal([]byte(tc.data), &money))
+			assert.True(t, decimal.RequireFromString("0.3").Equal(money.Amount))
+			assert.Equal(t, tc.format, money.Format)
+			assert.Equal(t, tc.currency, money.Currency)
+		})
+	}
+
+	var money Money
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"abc"}`), &money))
+}
+
+func TestMoneyFormatFromRequest(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target      string
+		accept      string
+		want        MoneyFormat
+		ok          bool
+		vary        string
+		contentType string
+	}{
+		"default":            {target: "/", want: MoneyFormatFloat, ok: true, vary: "Accept"},
+		"string query":       {target: "/?money=string", want: MoneyFormatString, ok: true},
+		"object query":       {target: "/?money=object", want: MoneyFormatObject, ok: true},
+		"v2 accept":          {target: "/", accept: "application/json, " + MediaTypeV2 + ";q=0.9", want: MoneyFormatObject, ok: true, vary: "Accept", contentType: MediaTypeV2},
+		"query beats accept": {target: "/?money=float", accept: MediaTypeV2, want: MoneyFormatFloat, ok: true},
+		"unknown query":      {target: "/?money=cents", want: MoneyFormatFloat, ok: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			res := httptest.NewRecorder()
+			format, ok := MoneyFormatFromRequest(res, req)
+			assert.Equal(t, tc.want, format)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.vary, res.Header().Get("Vary"))
+			assert.Equal(t, tc.contentType, res.Header().Get("Content-Type"))
+		})
+	}
+}
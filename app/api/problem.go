@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+)
+
+// Error codes shared by all endpoints. Domain errors have their own codes, see DomainErrorResponse.
+const (
+	CodeInvalidRequestBody    = "invalid_request_body"
+	CodeValidationFailed      = "validation_failed"
+	CodeInvalidQueryParameter = "invalid_query_parameter"
+	CodeInvalidPathParameter  = "invalid_path_parameter"
+	CodeUnsupportedMediaType  = "unsupported_media_type"
+	CodePayloadTooLarge       = "payload_too_large"
+	CodeTimeout               = "timeout"
+	CodeInternal              = "internal_error"
+)
+
+// Field error codes describing why a single field was rejected.
+const (
+	FieldRequired   = "required"
+	FieldInvalid    = "invalid"
+	FieldTooLong    = "too_long"
+	FieldNotFound   = "not_found"
+	FieldOutOfRange = "out_of_range"
+	FieldUnknown    = "unknown"
+)
+
+// problemTypePrefix prefixes the error code to build the problem type URI.
+const problemTypePrefix = "urn:problem:"
+
+// Problem is an RFC 7807 problem details object. Code is a stable machine-readable error code,
+// Errors lists the rejected fields of validation errors and RequestID correlates the error with logs.
+// ProductCount is an extension member reporting the products that still reference a category in use.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+
+	ProductCount *int64 `json:"product_count,omitempty"`
+}
+
+// FieldError reports why a request body field or query parameter was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProblemResponse writes a problem+json payload. The type, title, instance and request ID are derived
+// from the code, status and request when they are empty. The request ID is read from the context,
+// falling back to the X-Request-ID request header.
+func ProblemResponse(w http.ResponseWriter, r *http.Request, problem Problem) {
+	if problem.Type == "" {
+		problem.Type = problemTypePrefix + problem.Code
+	}
+
+	if problem.Title == "" {
+		problem.Title = problemTitle(problem.Code)
+	}
+
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+
+	if problem.RequestID == "" {
+		problem.RequestID = requestid.FromContext(r.Context())
+	}
+
+	if problem.RequestID == "" {
+		problem.RequestID = r.Header.Get(requestid.Header)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+	}
+}
+
+// ErrorResponse writes a problem with the provided HTTP status, error code and detail message.
+func ErrorResponse(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	ProblemResponse(w, r, Problem{Status: status, Code: code, Detail: detail})
+}
+
+// ValidationErrorResponse writes an HTTP 400 problem listing the rejected fields.
+// The detail joins the messages of the fields.
+func ValidationErrorResponse(w http.ResponseWriter, r *http.Request, code string, fields ...FieldError) {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+
+	ProblemResponse(w, r, Problem{
+		Status: http.StatusBadRequest,
+		Code:   code,
+		Detail: strings.Join(messages, "; "),
+		Errors: fields,
+	})
+}
+
+// InvalidQueryParameterResponse writes an HTTP 400 problem rejecting the named query parameter.
+func InvalidQueryParameterResponse(w http.ResponseWriter, r *http.Request, name string) {
+	ValidationErrorResponse(w, r, CodeInvalidQueryParameter, FieldError{
+		Field:   name,
+		Code:    FieldInvalid,
+		Message: "invalid query parameter: " + name,
+	})
+}
+
+// InvalidFieldResponse writes an HTTP 400 validation problem rejecting a single request body field.
+func InvalidFieldResponse(w http.ResponseWriter, r *http.Request, field, code, message string) {
+	ValidationErrorResponse(w, r, CodeValidationFailed, FieldError{Field: field, Code: code, Message: message})
+}
+
+// problemTitle turns an error code such as product_not_found into the title "Product not found".
+func problemTitle(code string) string {
+	if code == "" {
+		return ""
+	}
+
+	title := strings.ReplaceAll(code, "_", " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// FieldErrors collects the rejected fields of a request.
+type FieldErrors []FieldError
+
+// Require records name as a missing required field unless present is true.
+func (f *FieldErrors) Require(name string, present bool) {
+	if !present {
+		*f = append(*f, FieldError{Field: name, Code: FieldRequired, Message: name + " is required"})
+	}
+}
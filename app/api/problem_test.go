@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorResponse(t *testing.T) {
+	t.Run("problem json response for a given http status code", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/catalog/PROD001", nil)
+		ErrorResponse(recorder, request, http.StatusInternalServerError, CodeInternal, "Some error occurred")
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+
+		expected := `{
+			"type": "urn:problem:internal_error",
+			"title": "Internal error",
+			"status": 500,
+			"code": "internal_error",
+			"detail": "Some error occurred",
+			"instance": "/catalog/PROD001"
+		}`
+		assert.JSONEq(t, expected, recorder.Body.String())
+	})
+
+	t.Run("request id from the context", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		request.Header.Set(requestid.Header, "from-header")
+		request = request.WithContext(requestid.NewContext(request.Context(), "from-context"))
+		ErrorResponse(recorder, request, http.StatusBadRequest, CodeInvalidRequestBody, "invalid request body")
+
+		assert.Contains(t, recorder.Body.String(), `"request_id":"from-context"`)
+	})
+
+	t.Run("request id from the request header", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		request.Header.Set(requestid.Header, "from-header")
+		ErrorResponse(recorder, request, http.StatusBadRequest, CodeInvalidRequestBody, "invalid request body")
+
+		assert.Contains(t, recorder.Body.String(), `"request_id":"from-header"`)
+	})
+}
+
+func TestErrorResponseEncodeErrorPath(t *testing.T) {
+	t.Run("encode error branch", func(t *testing.T) {
+		writer := &failingResponseWriter{}
+		request := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		ErrorResponse(writer, request, http.StatusBadRequest, CodeInvalidRequestBody, "error")
+
+		assert.Equal(t, http.StatusInternalServerError, writer.code)
+	})
+}
+
+func TestValidationErrorResponse(t *testing.T) {
+	t.Run("http400 listing the rejected fields", func(t *testing.T) {
+		var fields FieldErrors
+		fields.Require("code", false)
+		fields.Require("name", true)
+		fields.Require("price", false)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/catalog", nil)
+		ValidationErrorResponse(recorder, request, CodeValidationFailed, fields...)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		expected := `{
+			"type": "urn:problem:validation_failed",
+			"title": "Validation failed",
+			"status": 400,
+			"code": "validation_failed",
+			"detail": "code is required; price is required",
+			"instance": "/catalog",
+			"errors": [
+				{"field": "code", "code": "required", "message": "code is required"},
+				{"field": "price", "code": "required", "message": "price is required"}
+			]
+		}`
+		assert.JSONEq(t, expected, recorder.Body.String())
+	})
+}
+
+func TestInvalidQueryParameterResponse(t *testing.T) {
+	t.Run("http400 rejecting the query parameter", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/catalog?limit=abc", nil)
+		InvalidQueryParameterResponse(recorder, request, "limit")
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		expected := `{
+			"type": "urn:problem:invalid_query_parameter",
+			"title": "Invalid query parameter",
+			"status": 400,
+			"code": "invalid_query_parameter",
+			"detail": "invalid query parameter: limit",
+			"instance": "/catalog",
+			"errors": [{"field": "limit", "code": "invalid", "message": "invalid query parameter: limit"}]
+		}`
+		assert.JSONEq(t, expected, recorder.Body.String())
+	})
+}
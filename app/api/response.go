@@ -7,7 +7,7 @@ import (
 
 // OKResponse writes a JSON payload with HTTP 200 status.
 func OKResponse(w http.ResponseWriter, data any) {
-	w.Header().Set("Content-Type", "application/json")
+	setJSONContentType(w)
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
@@ -16,18 +16,31 @@ func OKResponse(w http.ResponseWriter, data any) {
 
 // CreatedResponse writes a JSON payload with HTTP 201 status.
 func CreatedResponse(w http.ResponseWriter, data any) {
-	w.Header().Set("Content-Type", "application/json")
+	setJSONContentType(w)
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
 	}
 }
 
-// ErrorResponse writes a JSON error payload with the provided HTTP status.
-func ErrorResponse(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
+// JSONResponse writes a JSON payload with the provided HTTP status.
+func JSONResponse(w http.ResponseWriter, status int, data any) {
+	setJSONContentType(w)
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(map[string]string{"error": message}); err != nil {
+	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
 	}
 }
+
+// NoContentResponse writes an empty HTTP 204 response.
+func NoContentResponse(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setJSONContentType sets the JSON content type unless the handler negotiated a more specific one,
+// see MoneyFormatFromRequest.
+func setJSONContentType(w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+}
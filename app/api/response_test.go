@@ -49,19 +49,6 @@ func TestOKResponse(t *testing.T) {
 	})
 }
 
-func TestErrorResponse(t *testing.T) {
-	t.Run("json response for a given http status code", func(t *testing.T) {
-		recorder := httptest.NewRecorder()
-		ErrorResponse(recorder, http.StatusInternalServerError, "Some error occurred")
-
-		assert.Equal(t, http.StatusInternalServerError, recorder.Code, "Expected status code 500 Internal Server Error")
-		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"), "Expected Content-Type to be application/json")
-
-		expected := `{"error":"Some error occurred"}`
-		assert.JSONEq(t, expected, recorder.Body.String(), "Response body does not match expected")
-	})
-}
-
 func TestOKResponseEncodeErrorPath(t *testing.T) {
 	t.Run("encode error branch", func(t *testing.T) {
 		writer := &failingResponseWriter{}
@@ -71,15 +58,6 @@ func TestOKResponseEncodeErrorPath(t *testing.T) {
 	})
 }
 
-func TestErrorResponseEncodeErrorPath(t *testing.T) {
-	t.Run("encode error branch", func(t *testing.T) {
-		writer := &failingResponseWriter{}
-		ErrorResponse(writer, http.StatusBadRequest, "error")
-
-		assert.Equal(t, http.StatusInternalServerError, writer.code)
-	})
-}
-
 func TestCreatedResponse(t *testing.T) {
 	t.Run("succesful http201 json response", func(t *testing.T) {
 		recorder := httptest.NewRecorder()
@@ -90,3 +68,24 @@ func TestCreatedResponse(t *testing.T) {
 		assert.JSONEq(t, `{"status":"created"}`, recorder.Body.String())
 	})
 }
+
+func TestJSONResponse(t *testing.T) {
+	t.Run("json response with a given http status code", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		JSONResponse(recorder, http.StatusUnprocessableEntity, map[string]string{"status": "rejected"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"status":"rejected"}`, recorder.Body.String())
+	})
+}
+
+func TestNoContentResponse(t *testing.T) {
+	t.Run("empty http204 response", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NoContentResponse(recorder)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+}
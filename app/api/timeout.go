@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithQueryTimeout bounds the request context of next, and so the database queries issued with it, to timeout.
+// A zero timeout leaves the request context untouched.
+func WithQueryTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next(w, r.WithContext(ctx))
+	}
+}
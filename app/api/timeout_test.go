@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Run("bounds the request context", func(t *testing.T) {
+		var deadline time.Time
+		var hasDeadline bool
+		handler := WithQueryTimeout(time.Minute, func(w http.ResponseWriter, r *http.Request) {
+			deadline, hasDeadline = r.Context().Deadline()
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/catalog", nil))
+
+		assert.True(t, hasDeadline)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+	})
+
+	t.Run("zero timeout leaves the request context untouched", func(t *testing.T) {
+		var hasDeadline bool
+		handler := WithQueryTimeout(0, func(w http.ResponseWriter, r *http.Request) {
+			_, hasDeadline = r.Context().Deadline()
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/catalog", nil))
+
+		assert.False(t, hasDeadline)
+	})
+}
@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRateReader defines the exchange rate lookups consumed by catalog handlers.
+type ExchangeRateReader interface {
+	GetExchangeRate(ctx context.Context, from, to string) (decimal.Decimal, error)
+}
+
+// priceConverter converts product prices into a target currency.
+// Rates are cached so that each currency pair is looked up once per request.
+// A nil converter leaves prices in their stored currency.
+type priceConverter struct {
+	target string
+	rates  ExchangeRateReader
+	cache  map[string]decimal.Decimal
+}
+
+func newPriceConverter(rates ExchangeRateReader, target string) *priceConverter {
+	if target == "" {
+		return nil
+	}
+
+	return &priceConverter{target: target, rates: rates, cache: map[string]decimal.Decimal{}}
+}
+
+// convertPricing returns a copy of the resolved prices in the target currency.
+// Converted amounts are rounded to cents.
+func (c *priceConverter) convertPricing(ctx context.Context, pricing models.ProductPricing) (models.ProductPricing, error) {
+	if c == nil || pricing.Currency == c.target {
+		return pricing, nil
+	}
+
+	rate, err := c.rate(ctx, pricing.Currency)
+	if err != nil {
+		return models.ProductPricing{}, err
+	}
+
+	converted := models.ProductPricing{
+		Currency: c.target,
+		Price:    convertPrice(pricing.Price, rate),
+		Variants: make([]models.ResolvedPrice, len(pricing.Variants)),
+	}
+	for i, price := range pricing.Variants {
+		converted.Variants[i] = convertPrice(price, rate)
+	}
+
+	return converted, nil
+}
+
+func convertPrice(price models.ResolvedPrice, rate decimal.Decimal) models.ResolvedPrice {
+	price.Original = price.Original.Mul(rate).Round(2)
+	if price.Sale != nil {
+		sale := price.Sale.Mul(rate).Round(2)
+		price.Sale = &sale
+	}
+
+	return price
+}
+
+// check reports an error when prices in currency from cannot be converted into the target currency.
+// A nil converter converts nothing and accepts every currency.
+func (c *priceConverter) check(ctx context.Context, from string) error {
+	if c == nil || from == c.target {
+		return nil
+	}
+
+	_, err := c.rate(ctx, from)
+	return err
+}
+
+func (c *priceConverter) rate(ctx context.Context, from string) (decimal.Decimal, error) {
+	if rate, ok := c.cache[from]; ok {
+		return rate, nil
+	}
+
+	rate, err := c.rates.GetExchangeRate(ctx, from, c.target)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	c.cache[from] = rate
+	return rate, nil
+}
@@ -0,0 +1,82 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursorPayload is the JSON document wrapped by opaque catalog cursors.
+// Sort records the sort key the cursor was issued for and Value the sorted column of the last product.
+// Currency records the price currency of cursors issued for the price sort order.
+type cursorPayload struct {
+	ID       uint   `json:"id"`
+	Sort     string `json:"sort,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// encodeCursor returns the opaque cursor pointing after the given product in the filter's sort order.
+func encodeCursor(product models.Product, filter models.ProductCatalogFilter) string {
+	payload := cursorPayload{ID: product.ID, Sort: sortKey(filter)}
+	switch filter.SortBy {
+	case models.SortByPrice:
+		payload.Value = product.SortPrice.String()
+		payload.Currency = filter.PriceCurrency
+	case models.SortByCode:
+		payload.Value = product.Code
+	case models.SortByCreatedAt:
+		payload.Value = product.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	raw, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses an opaque cursor issued for the filter's sort order.
+// An empty cursor means the first page and decodes to nil.
+func decodeCursor(raw string, filter models.ProductCatalogFilter) (*models.ProductCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(decoded, &payload); err != nil || payload.ID == 0 {
+		return nil, errInvalidCursor
+	}
+
+	if payload.Sort != sortKey(filter) {
+		return nil, errInvalidCursor
+	}
+
+	cursor := &models.ProductCursor{ID: payload.ID}
+	switch filter.SortBy {
+	case models.SortByPrice:
+		price, err := decimal.NewFromString(payload.Value)
+		if err != nil || payload.Currency != filter.PriceCurrency {
+			return nil, errInvalidCursor
+		}
+		cursor.Value = price
+	case models.SortByCode:
+		cursor.Value = payload.Value
+	case models.SortByCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339Nano, payload.Value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		cursor.Value = createdAt
+	}
+
+	return cursor, nil
+}
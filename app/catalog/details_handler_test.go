@@ -5,11 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/gorm"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCatalogHandleGetByCodeSuccessWithVariantFallback(t *testing.T) {
@@ -25,13 +26,16 @@ func TestCatalogHandleGetByCodeSuccessWithVariantFallback(t *testing.T) {
 				Name: "Clothing",
 			},
 			Variants: []models.Variant{
-				{Name: "Variant A", SKU: "SKU001A", Price: &variantPrice},
+				{Name: "Variant A", SKU: "SKU001A", Price: &variantPrice, StockLevels: []models.StockLevel{
+					{Warehouse: "default", Quantity: 2},
+					{Warehouse: "berlin", Quantity: 4, Reserved: 1},
+				}},
 				{Name: "Variant B", SKU: "SKU001B", Price: nil},
 			},
 		},
 	}
 
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001", nil)
 	req.SetPathValue("code", "PROD001")
 	res := httptest.NewRecorder()
@@ -46,15 +50,69 @@ func TestCatalogHandleGetByCodeSuccessWithVariantFallback(t *testing.T) {
 	assert.Equal(t, "PROD001", payload.Code)
 	assert.Equal(t, "CLOTHING", payload.Category.Code)
 	assert.Len(t, payload.Variants, 2)
-	assert.Equal(t, 11.99, payload.Variants[0].Price)
-	assert.Equal(t, 10.99, payload.Variants[1].Price)
+	assert.Equal(t, "11.99", payload.Variants[0].Price.Amount.String())
+	assert.Equal(t, "10.99", payload.Variants[1].Price.Amount.String())
+	assert.Equal(t, 5, payload.Variants[0].Stock)
+	assert.True(t, payload.Variants[0].InStock)
+	assert.Zero(t, payload.Variants[1].Stock)
+	assert.False(t, payload.Variants[1].InStock)
+}
+
+func TestCatalogHandleGetByCodeConvertsCurrency(t *testing.T) {
+	t.Parallel()
+
+	variantPrice := decimal.RequireFromString("11.99")
+	mock := &productsReaderMock{
+		productByCode: &models.Product{
+			Code:     "PROD001",
+			Price:    decimal.RequireFromString("10.99"),
+			Currency: "EUR",
+			Variants: []models.Variant{
+				{Name: "Variant A", SKU: "SKU001A", Price: &variantPrice},
+				{Name: "Variant B", SKU: "SKU001B"},
+			},
+		},
+	}
+	rates := &exchangeRatesMock{rates: map[string]decimal.Decimal{"EURUSD": decimal.RequireFromString("1.08")}}
+
+	handler := NewCatalogHandler(mock, rates, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001?currency=USD", nil)
+	req.SetPathValue("code", "PROD001")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var payload ProductDetailsResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "11.87", payload.Price.Amount.String())
+	assert.Equal(t, "USD", payload.Currency)
+	assert.Equal(t, "12.95", payload.Variants[0].Price.Amount.String())
+	assert.Equal(t, "11.87", payload.Variants[1].Price.Amount.String())
+	assert.Equal(t, "USD", payload.Variants[1].Currency)
+	assert.True(t, decimal.RequireFromString("11.99").Equal(variantPrice))
+}
+
+func TestCatalogHandleGetByCodeUnsupportedCurrency(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{productByCode: &models.Product{Code: "PROD001", Price: decimal.NewFromInt(1)}}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001?currency=JPY", nil)
+	req.SetPathValue("code", "PROD001")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
 }
 
 func TestCatalogHandleGetByCodeNotFound(t *testing.T) {
 	t.Parallel()
 
-	mock := &productsReaderMock{err: gorm.ErrRecordNotFound}
-	handler := NewCatalogHandler(mock)
+	mock := &productsReaderMock{err: models.ErrProductNotFound}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog/MISSING", nil)
 	req.SetPathValue("code", "MISSING")
 	res := httptest.NewRecorder()
@@ -68,7 +126,7 @@ func TestCatalogHandleGetByCodeMissingCode(t *testing.T) {
 	t.Parallel()
 
 	mock := &productsReaderMock{}
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog/", nil)
 	res := httptest.NewRecorder()
 
@@ -81,7 +139,7 @@ func TestCatalogHandleGetByCodeRepositoryError(t *testing.T) {
 	t.Parallel()
 
 	mock := &productsReaderMock{err: assert.AnError}
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001", nil)
 	req.SetPathValue("code", "PROD001")
 	res := httptest.NewRecorder()
@@ -90,3 +148,58 @@ func TestCatalogHandleGetByCodeRepositoryError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.Code)
 }
+
+func TestCatalogHandleGetByCodeSalePrices(t *testing.T) {
+	t.Parallel()
+
+	variantAPrice := decimal.RequireFromString("11.99")
+	variantCPrice := decimal.RequireFromString("9.50")
+	variantA := uint(11)
+	endsAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := &productsReaderMock{
+		productByCode: &models.Product{
+			ID:    1,
+			Code:  "PROD001",
+			Price: decimal.RequireFromString("10.99"),
+			Variants: []models.Variant{
+				{ID: 11, Name: "Variant A", SKU: "SKU001A", Price: &variantAPrice},
+				{ID: 12, Name: "Variant B", SKU: "SKU001B"},
+				{ID: 13, Name: "Variant C", SKU: "SKU001C", Price: &variantCPrice},
+			},
+		},
+	}
+	prices := &priceBooksMock{book: models.NewPriceBook(nil, nil, []models.SalePrice{
+		{ProductID: 1, Price: decimal.RequireFromString("8.99"), EndsAt: endsAt},
+		{ProductID: 1, VariantID: &variantA, Price: decimal.RequireFromString("10.99"), EndsAt: endsAt},
+	})}
+	rates := &exchangeRatesMock{rates: map[string]decimal.Decimal{"EURUSD": decimal.NewFromInt(2)}}
+
+	handler := NewCatalogHandler(mock, rates, prices)
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001?currency=USD&money=string", nil)
+	req.SetPathValue("code", "PROD001")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []uint{1}, prices.capturedIDs)
+
+	var payload ProductDetailsResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "17.98", payload.Price.Amount.String())
+	require.NotNil(t, payload.OriginalPrice)
+	assert.Equal(t, "21.98", payload.OriginalPrice.Amount.String())
+	require.NotNil(t, payload.SaleEndsAt)
+	assert.True(t, endsAt.Equal(*payload.SaleEndsAt))
+
+	require.Len(t, payload.Variants, 3)
+	assert.Equal(t, "21.98", payload.Variants[0].Price.Amount.String())
+	require.NotNil(t, payload.Variants[0].OriginalPrice)
+	assert.Equal(t, "23.98", payload.Variants[0].OriginalPrice.Amount.String())
+	assert.Equal(t, "17.98", payload.Variants[1].Price.Amount.String())
+	require.NotNil(t, payload.Variants[1].OriginalPrice)
+	assert.Equal(t, "21.98", payload.Variants[1].OriginalPrice.Amount.String())
+	assert.Equal(t, "19", payload.Variants[2].Price.Amount.String())
+	assert.Nil(t, payload.Variants[2].OriginalPrice)
+	assert.Nil(t, payload.Variants[2].SaleEndsAt)
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/importer"
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// exportContentTypes maps export formats to their response content type.
+var exportContentTypes = map[importer.Format]string{
+	importer.FormatCSV:    "text/csv; charset=utf-8",
+	importer.FormatNDJSON: "application/x-ndjson",
+}
+
+// ProductExporter defines the export operation consumed by the export handler.
+type ProductExporter interface {
+	ExportProducts(ctx context.Context, filter models.ProductCatalogFilter, fn func(models.Product) error) error
+}
+
+// ExportHandler exposes the HTTP handler streaming the full catalog.
+type ExportHandler struct {
+	repo   ProductExporter
+	logger *slog.Logger
+}
+
+// NewExportHandler creates a new ExportHandler logging failures after streaming has started to logger.
+func NewExportHandler(repo ProductExporter, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{repo: repo, logger: logger}
+}
+
+// HandleGet streams every product matching the catalog filters, with its variants, as CSV or NDJSON
+// in the import format. Pagination parameters are ignored and prices are the stored base prices.
+// Once streaming has started a failure can no longer change the status, so it truncates the response.
+func (h *ExportHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := importer.FormatCSV
+	if query.Has("format") {
+		var ok bool
+		if format, ok = importer.ParseFormat(query.Get("format")); !ok {
+			api.InvalidQueryParameterResponse(w, r, "format")
+			return
+		}
+	}
+
+	filter, invalid := parseCatalogFilter(query)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	writer, err := importer.NewWriter(w, format)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to export catalog")
+		return
+	}
+
+	var started bool
+	start := func() {
+		started = true
+		w.Header().Set("Content-Type", exportContentTypes[format])
+		w.Header().Set("Content-Disposition", `attachment; filename="catalog.`+string(format)+`"`)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	err = h.repo.ExportProducts(r.Context(), filter, func(product models.Product) error {
+		if !started {
+			start()
+		}
+
+		return writer.WriteProduct(product)
+	})
+	if err != nil {
+		if !started {
+			api.DomainErrorResponse(w, r, err, "failed to export catalog")
+			return
+		}
+
+		h.logFailure(r, err)
+		return
+	}
+
+	if !started {
+		start()
+	}
+
+	if err := writer.Flush(); err != nil {
+		h.logFailure(r, err)
+	}
+}
+
+// logFailure logs an export failing after the response status has been sent.
+func (h *ExportHandler) logFailure(r *http.Request, err error) {
+	h.logger.LogAttrs(r.Context(), slog.LevelError, "catalog export failed",
+		slog.String("error", err.Error()),
+		slog.String("request_id", requestid.FromContext(r.Context())),
+	)
+}
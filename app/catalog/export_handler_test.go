@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type productExporterMock struct {
+	products       []models.Product
+	err            error
+	called         bool
+	capturedFilter models.ProductCatalogFilter
+}
+
+func (m *productExporterMock) ExportProducts(_ context.Context, filter models.ProductCatalogFilter, fn func(models.Product) error) error {
+	m.called = true
+	m.capturedFilter = filter
+	for _, product := range m.products {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	return m.err
+}
+
+func exportProducts() []models.Product {
+	return []models.Product{
+		{
+			Code:     "PROD001",
+			Name:     "Product 1",
+			Price:    decimal.RequireFromString("10.99"),
+			Currency: "EUR",
+			Category: models.Category{Code: "CLOTHING"},
+			Variants: []models.Variant{{SKU: "SKU001A", Name: "Variant A"}},
+		},
+		{
+			Code:     "PROD002",
+			Name:     "Product 2",
+			Price:    decimal.RequireFromString("12.49"),
+			Currency: "EUR",
+			Category: models.Category{Code: "SHOES"},
+		},
+	}
+}
+
+func TestExportHandleGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams csv with catalog filters", func(t *testing.T) {
+		mock := &productExporterMock{products: exportProducts()}
+		handler := NewExportHandler(mock, slog.New(slog.DiscardHandler))
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, httptest.NewRequest(http.MethodGet, "/catalog/export?category=clothing&in_stock=true&sort=-price&limit=5", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", res.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="catalog.csv"`, res.Header().Get("Content-Disposition"))
+		assert.Equal(t, []string{"clothing"}, mock.capturedFilter.Categories)
+		require.NotNil(t, mock.capturedFilter.InStock)
+		assert.True(t, *mock.capturedFilter.InStock)
+		assert.Equal(t, models.SortByPrice, mock.capturedFilter.SortBy)
+		assert.True(t, mock.capturedFilter.SortDesc)
+
+		assert.Equal(t, strings.Join([]string{
+			"type,code,name,description,price,currency,category,parent,product,sku",
+			"product,PROD001,Product 1,,10.99,EUR,CLOTHING,,,",
+			"variant,,Variant A,,,,,,PROD001,SKU001A",
+			"product,PROD002,Product 2,,12.49,EUR,SHOES,,,",
+			"",
+		}, "\n"), res.Body.String())
+	})
+
+	t.Run("streams ndjson", func(t *testing.T) {
+		handler := NewExportHandler(&productExporterMock{products: exportProducts()}, slog.New(slog.DiscardHandler))
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, httptest.NewRequest(http.MethodGet, "/catalog/export?format=ndjson", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/x-ndjson", res.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.JSONEq(t, `{"type":"variant","name":"Variant A","product":"PROD001","sku":"SKU001A"}`, lines[1])
+	})
+
+	t.Run("empty catalog writes csv header", func(t *testing.T) {
+		handler := NewExportHandler(&productExporterMock{}, slog.New(slog.DiscardHandler))
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, httptest.NewRequest(http.MethodGet, "/catalog/export", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "type,code,name,description,price,currency,category,parent,product,sku\n", res.Body.String())
+	})
+
+	t.Run("invalid query parameters", func(t *testing.T) {
+		for _, target := range []string{"/catalog/export?format=xml", "/catalog/export?sort=colour", "/catalog/export?price_lt=abc"} {
+			mock := &productExporterMock{}
+			handler := NewExportHandler(mock, slog.New(slog.DiscardHandler))
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, httptest.NewRequest(http.MethodGet, target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, target)
+			assert.False(t, mock.called, target)
+		}
+	})
+
+	t.Run("error before streaming", func(t *testing.T) {
+		handler := NewExportHandler(&productExporterMock{err: errors.New("db down")}, slog.New(slog.DiscardHandler))
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, httptest.NewRequest(http.MethodGet, "/catalog/export", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.JSONEq(t, `{"type":"urn:problem:internal_error","title":"Internal error","status":500,"code":"internal_error","detail":"failed to export catalog","instance":"/catalog/export"}`, res.Body.String())
+	})
+
+	t.Run("error while streaming truncates the response", func(t *testing.T) {
+		var logs bytes.Buffer
+		handler := NewExportHandler(&productExporterMock{products: exportProducts(), err: errors.New("connection lost")}, slog.New(slog.NewJSONHandler(&logs, nil)))
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, httptest.NewRequest(http.MethodGet, "/catalog/export?format=ndjson", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NotContains(t, res.Body.String(), "error")
+		assert.Contains(t, logs.String(), `"msg":"catalog export failed","error":"connection lost"`)
+	})
+}
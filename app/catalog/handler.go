@@ -1,27 +1,83 @@
 package catalog
 
 import (
-	"errors"
+	"context"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/shopspring/decimal"
-	"gorm.io/gorm"
 )
 
 // Response represents the catalog listing payload.
+// Offset and Limit are the applied pagination values.
+// Facets is only present when requested with the facets query parameter.
 type Response struct {
 	Products []Product `json:"products"`
 	Total    int64     `json:"total"`
+	Offset   int       `json:"offset"`
+	Limit    int       `json:"limit"`
+	Facets   *Facets   `json:"facets,omitempty"`
+}
+
+// CursorResponse represents a keyset catalog page.
+// Limit is the applied page size and NextCursor is empty on the last page.
+type CursorResponse struct {
+	Products   []Product `json:"products"`
+	Limit      int       `json:"limit"`
+	NextCursor string    `json:"next_cursor"`
+	Facets     *Facets   `json:"facets,omitempty"`
+}
+
+// Default and maximum page sizes of catalog listings.
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Facets represents aggregated counts of the products matching the current filter.
+type Facets struct {
+	Categories []CategoryFacet `json:"categories,omitempty"`
+	Prices     []PriceFacet    `json:"prices,omitempty"`
+}
+
+// CategoryFacet represents the number of matching products in a category.
+type CategoryFacet struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// PriceFacet represents the number of matching products priced in [min, max).
+// Open-ended buckets omit min or max.
+type PriceFacet struct {
+	Min   *api.Money `json:"min,omitempty"`
+	Max   *api.Money `json:"max,omitempty"`
+	Count int64      `json:"count"`
+}
+
+// priceFacetBoundaries are the limits of the price facet buckets.
+var priceFacetBoundaries = []decimal.Decimal{
+	decimal.NewFromInt(10),
+	decimal.NewFromInt(25),
+	decimal.NewFromInt(50),
+	decimal.NewFromInt(100),
 }
 
 // Product represents a single product in the catalog response.
+// Price is the price to pay; OriginalPrice and SaleEndsAt are only present during a sale.
 type Product struct {
-	Code     string   `json:"code"`
-	Price    float64  `json:"price"`
-	Category Category `json:"category"`
+	Code          string     `json:"code"`
+	Name          string     `json:"name"`
+	Price         api.Money  `json:"price"`
+	OriginalPrice *api.Money `json:"original_price,omitempty"`
+	SaleEndsAt    *time.Time `json:"sale_ends_at,omitempty"`
+	Currency      string     `json:"currency"`
+	Category      Category   `json:"category"`
 }
 
 // Category represents category data in catalog responses.
@@ -32,57 +88,355 @@ type Category struct {
 
 // ProductReader defines read operations consumed by catalog handlers.
 type ProductReader interface {
-	ListProducts(filter models.ProductCatalogFilter) ([]models.Product, int64, error)
-	GetProductByCode(code string) (*models.Product, error)
+	ListProducts(ctx context.Context, filter models.ProductCatalogFilter) ([]models.Product, int64, error)
+	ListProductsAfter(ctx context.Context, filter models.ProductCatalogFilter, after *models.ProductCursor) ([]models.Product, bool, error)
+	SearchProducts(ctx context.Context, text string, filter models.ProductCatalogFilter) ([]models.Product, int64, error)
+	GetProductFacets(ctx context.Context, filter models.ProductCatalogFilter, request models.FacetRequest) (*models.ProductFacets, error)
+	GetProductByCode(ctx context.Context, code string) (*models.Product, error)
+}
+
+// ProductWriter defines write operations consumed by catalog handlers.
+type ProductWriter interface {
+	CreateProduct(ctx context.Context, input models.ProductInput) (*models.Product, error)
+	UpdateProduct(ctx context.Context, code string, update models.ProductUpdate) (*models.Product, error)
+	DeleteProduct(ctx context.Context, code string) error
+}
+
+// ProductReaderWriter groups the product operations consumed by catalog handlers.
+type ProductReaderWriter interface {
+	ProductReader
+	ProductWriter
 }
 
 // CatalogHandler exposes HTTP handlers for catalog operations.
 type CatalogHandler struct {
-	repo           ProductReader
+	repo           ProductReaderWriter
+	rates          ExchangeRateReader
+	priceBooks     PriceBookReader
 	detailsService *detailsService
+	strict         bool
 }
 
 // NewCatalogHandler creates a new CatalogHandler.
-func NewCatalogHandler(r ProductReader) *CatalogHandler {
+func NewCatalogHandler(r ProductReaderWriter, rates ExchangeRateReader, priceBooks PriceBookReader) *CatalogHandler {
 	return &CatalogHandler{
 		repo:           r,
+		rates:          rates,
+		priceBooks:     priceBooks,
 		detailsService: newDetailsService(),
 	}
 }
 
 // HandleGet returns paginated catalog products with optional filters.
+// category, exclude_category and codes accept comma-separated lists.
+// price_lt, price_lte, price_gt and price_gte bound the price and price_match=variant
+// matches them against variant prices instead of the product price.
+// in_stock=true keeps products with a variant in stock, in_stock=false the sold-out ones.
+// Passing the cursor query parameter, even empty, switches from offset to keyset pagination.
+// facets=category,price adds counts per category and price bucket for the same filter.
+// market and at resolve the price list and sales of a market at a point in time, and
+// currency converts the returned prices. Products missing from the market's price list are
+// converted into the market currency unless currency is set. Price filters, the price sort order
+// and price facets compare stored prices converted into currency, EUR by default, and are
+// rejected with market since they would not match the market prices.
+// Prices are JSON numbers unless exact money is negotiated, see api.MoneyFormatFromRequest.
+// Malformed offset and limit values fall back to their defaults and are clamped to their range,
+// unless strict validation rejects them along with unknown parameters, see SetStrictQuery.
 func (h *CatalogHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
-	offset := parseOffset(query.Get("offset"))
-	limit := parseLimit(query.Get("limit"))
-
-	var priceLessThan *decimal.Decimal
-	if rawPriceLt := query.Get("price_lt"); rawPriceLt != "" {
-		parsed, err := decimal.NewFromString(rawPriceLt)
-		if err != nil {
-			api.ErrorResponse(w, http.StatusBadRequest, "invalid query parameter: price_lt")
+	if h.isStrict(w, r) {
+		if errs := validateStrictQuery(query); len(errs) > 0 {
+			api.ValidationErrorResponse(w, r, api.CodeInvalidQueryParameter, errs...)
 			return
 		}
-		priceLessThan = &parsed
 	}
 
-	res, total, err := h.repo.ListProducts(models.ProductCatalogFilter{
-		Offset:        offset,
-		Limit:         limit,
-		Category:      query.Get("category"),
-		PriceLessThan: priceLessThan,
-	})
+	filter, invalid := parseCatalogFilter(query)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	prices, invalid := h.parsePriceOptions(w, r)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	facetRequest, ok := parseFacets(query["facets"])
+	if !ok {
+		api.InvalidQueryParameterResponse(w, r, "facets")
+		return
+	}
+
+	if errs := marketPriceConflicts(query, filter, facetRequest); len(errs) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeInvalidQueryParameter, errs...)
+		return
+	}
+
+	if query.Has("cursor") {
+		h.handleGetPage(w, r, filter, facetRequest, prices, query.Get("cursor"))
+		return
+	}
+
+	res, total, err := h.repo.ListProducts(r.Context(), filter)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch products")
+		return
+	}
+
+	products, err := h.toProducts(r.Context(), res, prices)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
+		return
+	}
+
+	facets, err := h.buildFacets(r.Context(), filter, facetRequest, prices.format)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch facets")
+		return
+	}
+
+	response := Response{
+		Products: products,
+		Total:    total,
+		Offset:   filter.Offset,
+		Limit:    filter.Limit,
+		Facets:   facets,
+	}
+
+	api.OKResponse(w, response)
+}
+
+// handleGetPage serves a keyset page starting after the given opaque cursor.
+func (h *CatalogHandler) handleGetPage(
+	w http.ResponseWriter,
+	r *http.Request,
+	filter models.ProductCatalogFilter,
+	facetRequest *models.FacetRequest,
+	prices priceOptions,
+	rawCursor string,
+) {
+	after, err := decodeCursor(rawCursor, filter)
+	if err != nil {
+		api.InvalidQueryParameterResponse(w, r, "cursor")
+		return
+	}
+
+	res, hasMore, err := h.repo.ListProductsAfter(r.Context(), filter, after)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch products")
+		return
+	}
+
+	products, err := h.toProducts(r.Context(), res, prices)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
+		return
+	}
+
+	facets, err := h.buildFacets(r.Context(), filter, facetRequest, prices.format)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch facets")
+		return
+	}
+
+	response := CursorResponse{Products: products, Limit: filter.Limit, Facets: facets}
+	if hasMore && len(res) > 0 {
+		response.NextCursor = encodeCursor(res[len(res)-1], filter)
+	}
+
+	api.OKResponse(w, response)
+}
+
+// buildFacets loads the requested facets for the filter. A nil request yields nil facets.
+// Price bucket limits are in the filter's price currency.
+func (h *CatalogHandler) buildFacets(ctx context.Context, filter models.ProductCatalogFilter, request *models.FacetRequest, format api.MoneyFormat) (*Facets, error) {
+	if request == nil {
+		return nil, nil
+	}
+
+	res, err := h.repo.GetProductFacets(ctx, filter, *request)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := &Facets{}
+	for _, category := range res.Categories {
+		facets.Categories = append(facets.Categories, CategoryFacet{Code: category.Code, Name: category.Name, Count: category.Count})
+	}
+
+	currency := filter.PriceCurrency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	for _, price := range res.Prices {
+		facet := PriceFacet{Count: price.Count}
+		if price.Min != nil {
+			lower := api.NewMoney(*price.Min, currency, format)
+			facet.Min = &lower
+		}
+
+		if price.Max != nil {
+			upper := api.NewMoney(*price.Max, currency, format)
+			facet.Max = &upper
+		}
+
+		facets.Prices = append(facets.Prices, facet)
+	}
+
+	return facets, nil
+}
+
+// parseFacets parses the facets query parameter into a facet request.
+// It returns nil when no facet is requested and reports false for unknown facets.
+func parseFacets(values []string) (*models.FacetRequest, bool) {
+	names := parseList(values)
+	if len(names) == 0 {
+		return nil, true
+	}
+
+	request := &models.FacetRequest{}
+	for _, name := range names {
+		switch name {
+		case "category":
+			request.Categories = true
+		case "price":
+			request.PriceBoundaries = priceFacetBoundaries
+		default:
+			return nil, false
+		}
+	}
+
+	return request, true
+}
+
+// HandleSearch returns catalog products matching the full-text query q ordered by relevance.
+// It accepts the same pagination, category and price filters and price options as HandleGet.
+func (h *CatalogHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	text := strings.TrimSpace(query.Get("q"))
+	if text == "" {
+		api.ValidationErrorResponse(w, r, api.CodeInvalidQueryParameter, api.FieldError{Field: "q", Code: api.FieldRequired, Message: "missing query parameter: q"})
+		return
+	}
+
+	filter, invalid := parseCatalogFilter(query)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	prices, invalid := h.parsePriceOptions(w, r)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	if errs := marketPriceConflicts(query, filter, nil); len(errs) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeInvalidQueryParameter, errs...)
+		return
+	}
+
+	res, total, err := h.repo.SearchProducts(r.Context(), text, filter)
 	if err != nil {
-		api.ErrorResponse(w, http.StatusInternalServerError, "failed to fetch products")
+		api.DomainErrorResponse(w, r, err, "failed to search products")
 		return
 	}
 
+	products, err := h.toProducts(r.Context(), res, prices)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
+		return
+	}
+
+	api.OKResponse(w, Response{Products: products, Total: total, Offset: filter.Offset, Limit: filter.Limit})
+}
+
+// parseCatalogFilter builds the catalog filter from query parameters.
+// It returns the name of the first invalid parameter, or an empty string.
+func parseCatalogFilter(query url.Values) (models.ProductCatalogFilter, string) {
+	prices := make(map[string]*decimal.Decimal, 4)
+	for _, name := range []string{"price_lt", "price_lte", "price_gt", "price_gte"} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+
+		parsed, err := decimal.NewFromString(raw)
+		if err != nil {
+			return models.ProductCatalogFilter{}, name
+		}
+		prices[name] = &parsed
+	}
+
+	var matchVariantPrices bool
+	switch query.Get("price_match") {
+	case "", "product":
+	case "variant":
+		matchVariantPrices = true
+	default:
+		return models.ProductCatalogFilter{}, "price_match"
+	}
+
+	var inStock *bool
+	if raw := query.Get("in_stock"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return models.ProductCatalogFilter{}, "in_stock"
+		}
+		inStock = &parsed
+	}
+
+	sortBy, sortDesc, ok := parseSort(query.Get("sort"))
+	if !ok {
+		return models.ProductCatalogFilter{}, "sort"
+	}
+
+	priceCurrency, ok := parseCurrency(query.Get("currency"))
+	if !ok {
+		return models.ProductCatalogFilter{}, "currency"
+	}
+
+	return models.ProductCatalogFilter{
+		Offset:                  parseOffset(query.Get("offset")),
+		Limit:                   parseLimit(query.Get("limit")),
+		Categories:              parseList(query["category"]),
+		ExcludeCategories:       parseList(query["exclude_category"]),
+		Codes:                   parseList(query["codes"]),
+		PriceLessThan:           prices["price_lt"],
+		PriceLessThanOrEqual:    prices["price_lte"],
+		PriceGreaterThan:        prices["price_gt"],
+		PriceGreaterThanOrEqual: prices["price_gte"],
+		MatchVariantPrices:      matchVariantPrices,
+		PriceCurrency:           priceCurrency,
+		InStock:                 inStock,
+		SortBy:                  sortBy,
+		SortDesc:                sortDesc,
+	}, ""
+}
+
+// toProducts maps catalog products to their listing representation with resolved prices.
+func (h *CatalogHandler) toProducts(ctx context.Context, res []models.Product, prices priceOptions) ([]Product, error) {
+	pricing, err := h.resolvePrices(ctx, res, prices)
+	if err != nil {
+		return nil, err
+	}
+
 	products := make([]Product, len(res))
 	for i, p := range res {
+		currency := pricing[i].Currency
+		originalPrice, saleEndsAt := salePrice(pricing[i].Price, currency, prices.format)
 		products[i] = Product{
-			Code:  p.Code,
-			Price: p.Price.InexactFloat64(),
+			Code:          p.Code,
+			Name:          p.Name,
+			Price:         api.NewMoney(pricing[i].Price.Active(), currency, prices.format),
+			OriginalPrice: originalPrice,
+			SaleEndsAt:    saleEndsAt,
+			Currency:      currency,
 			Category: Category{
 				Code: p.Category.Code,
 				Name: p.Category.Name,
@@ -90,49 +444,126 @@ func (h *CatalogHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	response := Response{
-		Products: products,
-		Total:    total,
-	}
-
-	api.OKResponse(w, response)
+	return products, nil
 }
 
 // ProductDetailsResponse represents product details including variants.
+// OriginalPrice and SaleEndsAt are only present during a sale.
 type ProductDetailsResponse struct {
-	Code     string           `json:"code"`
-	Price    float64          `json:"price"`
-	Category Category         `json:"category"`
-	Variants []ProductVariant `json:"variants"`
+	Code          string           `json:"code"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	Price         api.Money        `json:"price"`
+	OriginalPrice *api.Money       `json:"original_price,omitempty"`
+	SaleEndsAt    *time.Time       `json:"sale_ends_at,omitempty"`
+	Currency      string           `json:"currency"`
+	Category      Category         `json:"category"`
+	Variants      []ProductVariant `json:"variants"`
 }
 
 // ProductVariant represents a variant in product details responses.
+// Stock is the unreserved quantity summed over all warehouses.
 type ProductVariant struct {
-	Name  string  `json:"name"`
-	SKU   string  `json:"sku"`
-	Price float64 `json:"price"`
+	Name          string     `json:"name"`
+	SKU           string     `json:"sku"`
+	Price         api.Money  `json:"price"`
+	OriginalPrice *api.Money `json:"original_price,omitempty"`
+	SaleEndsAt    *time.Time `json:"sale_ends_at,omitempty"`
+	Currency      string     `json:"currency"`
+	Stock         int        `json:"stock"`
+	InStock       bool       `json:"in_stock"`
 }
 
 // HandleGetByCode returns detailed product data by product code.
+// It accepts the same market, at, currency and money parameters as HandleGet.
 func (h *CatalogHandler) HandleGetByCode(w http.ResponseWriter, r *http.Request) {
 	code := r.PathValue("code")
 	if code == "" {
-		api.ErrorResponse(w, http.StatusBadRequest, "missing product code")
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code")
 		return
 	}
 
-	product, err := h.repo.GetProductByCode(code)
+	prices, invalid := h.parsePriceOptions(w, r)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	product, err := h.repo.GetProductByCode(r.Context(), code)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			api.ErrorResponse(w, http.StatusNotFound, "product not found")
-			return
-		}
+		api.DomainErrorResponse(w, r, err, "failed to fetch product details")
+		return
+	}
 
-		api.ErrorResponse(w, http.StatusInternalServerError, "failed to fetch product details")
+	details, err := h.productDetails(r.Context(), product, prices)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
 		return
 	}
 
-	api.OKResponse(w, h.detailsService.BuildProductDetails(product))
+	api.OKResponse(w, details)
+}
+
+// productDetails resolves the prices of a product and builds its details.
+func (h *CatalogHandler) productDetails(ctx context.Context, product *models.Product, prices priceOptions) (ProductDetailsResponse, error) {
+	pricing, err := h.resolvePrices(ctx, []models.Product{*product}, prices)
+	if err != nil {
+		return ProductDetailsResponse{}, err
+	}
+
+	return h.detailsService.BuildProductDetails(product, pricing[0], prices.format), nil
+}
+
+// parseCurrency normalizes the currency query parameter. An empty value keeps stored currencies.
+func parseCurrency(raw string) (string, bool) {
+	if raw == "" {
+		return "", true
+	}
+
+	return models.NormalizeCurrency(raw)
+}
+
+// parseList splits repeated and comma-separated query values, dropping blank entries.
+func parseList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// parseSort parses a sort key such as "price" or "-price", where a leading minus sorts descending.
+// An empty key sorts by id. It reports false for unknown keys.
+func parseSort(raw string) (models.ProductSortField, bool, bool) {
+	if raw == "" {
+		return models.SortByID, false, true
+	}
+
+	desc := strings.HasPrefix(raw, "-")
+	switch field := models.ProductSortField(strings.TrimPrefix(raw, "-")); field {
+	case models.SortByPrice, models.SortByCode, models.SortByCreatedAt:
+		return field, desc, true
+	default:
+		return "", false, false
+	}
+}
+
+// sortKey formats the filter's sort order back into its query parameter form.
+func sortKey(filter models.ProductCatalogFilter) string {
+	if filter.SortBy == "" || filter.SortBy == models.SortByID {
+		return ""
+	}
+
+	if filter.SortDesc {
+		return "-" + string(filter.SortBy)
+	}
+
+	return string(filter.SortBy)
 }
 
 func parseOffset(raw string) int {
@@ -146,20 +577,20 @@ func parseOffset(raw string) int {
 
 func parseLimit(raw string) int {
 	if raw == "" {
-		return 10
+		return defaultLimit
 	}
 
 	limit, err := strconv.Atoi(raw)
 	if err != nil {
-		return 10
+		return defaultLimit
 	}
 
 	if limit < 1 {
 		return 1
 	}
 
-	if limit > 100 {
-		return 100
+	if limit > maxLimit {
+		return maxLimit
 	}
 
 	return limit
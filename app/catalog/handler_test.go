@@ -1,15 +1,20 @@
 package catalog
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type productsReaderMock struct {
@@ -17,15 +22,50 @@ type productsReaderMock struct {
 	total         int64
 	err           error
 	capturedQuery models.ProductCatalogFilter
-	productByCode *models.Product
+	capturedAfter *models.ProductCursor
+	capturedText  string
+	capturedCtx   context.Context
+
+	facets         *models.ProductFacets
+	facetsErr      error
+	capturedFacets *models.FacetRequest
+	hasMore        bool
+	productByCode  *models.Product
+
+	writeErr       error
+	capturedCode   string
+	capturedInput  models.ProductInput
+	capturedUpdate models.ProductUpdate
 }
 
-func (m *productsReaderMock) ListProducts(filter models.ProductCatalogFilter) ([]models.Product, int64, error) {
+func (m *productsReaderMock) ListProducts(ctx context.Context, filter models.ProductCatalogFilter) ([]models.Product, int64, error) {
+	m.capturedCtx = ctx
 	m.capturedQuery = filter
 	return m.products, m.total, m.err
 }
 
-func (m *productsReaderMock) GetProductByCode(code string) (*models.Product, error) {
+func (m *productsReaderMock) ListProductsAfter(_ context.Context, filter models.ProductCatalogFilter, after *models.ProductCursor) ([]models.Product, bool, error) {
+	m.capturedQuery = filter
+	m.capturedAfter = after
+	return m.products, m.hasMore, m.err
+}
+
+func (m *productsReaderMock) SearchProducts(_ context.Context, text string, filter models.ProductCatalogFilter) ([]models.Product, int64, error) {
+	m.capturedText = text
+	m.capturedQuery = filter
+	return m.products, m.total, m.err
+}
+
+func (m *productsReaderMock) GetProductFacets(_ context.Context, filter models.ProductCatalogFilter, request models.FacetRequest) (*models.ProductFacets, error) {
+	m.capturedFacets = &request
+	if m.facetsErr != nil {
+		return nil, m.facetsErr
+	}
+
+	return m.facets, nil
+}
+
+func (m *productsReaderMock) GetProductByCode(_ context.Context, code string) (*models.Product, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -33,6 +73,73 @@ func (m *productsReaderMock) GetProductByCode(code string) (*models.Product, err
 	return m.productByCode, nil
 }
 
+func (m *productsReaderMock) CreateProduct(_ context.Context, input models.ProductInput) (*models.Product, error) {
+	m.capturedInput = input
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+
+	return &models.Product{
+		Code:     input.Code,
+		Price:    input.Price,
+		Category: models.Category{Code: input.CategoryCode},
+	}, nil
+}
+
+func (m *productsReaderMock) UpdateProduct(_ context.Context, code string, update models.ProductUpdate) (*models.Product, error) {
+	m.capturedCode = code
+	m.capturedUpdate = update
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+
+	return m.productByCode, nil
+}
+
+func (m *productsReaderMock) DeleteProduct(_ context.Context, code string) error {
+	m.capturedCode = code
+	return m.writeErr
+}
+
+type exchangeRatesMock struct {
+	rates map[string]decimal.Decimal
+	err   error
+	calls int
+}
+
+func (m *exchangeRatesMock) GetExchangeRate(_ context.Context, from, to string) (decimal.Decimal, error) {
+	m.calls++
+	if m.err != nil {
+		return decimal.Decimal{}, m.err
+	}
+
+	rate, ok := m.rates[from+to]
+	if !ok {
+		return decimal.Decimal{}, models.ErrUnsupportedConversion
+	}
+
+	return rate, nil
+}
+
+type priceBooksMock struct {
+	book           *models.PriceBook
+	err            error
+	capturedMarket string
+	capturedAt     time.Time
+	capturedIDs    []uint
+}
+
+func (m *priceBooksMock) GetPriceBook(_ context.Context, market string, at time.Time, productIDs []uint) (*models.PriceBook, error) {
+	m.capturedMarket = market
+	m.capturedAt = at
+	m.capturedIDs = productIDs
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.book, nil
+}
+
 func TestCatalogHandleGetDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -50,7 +157,7 @@ func TestCatalogHandleGetDefaults(t *testing.T) {
 		total: 8,
 	}
 
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
 	res := httptest.NewRecorder()
 
@@ -59,7 +166,7 @@ func TestCatalogHandleGetDefaults(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.Equal(t, 0, mock.capturedQuery.Offset)
 	assert.Equal(t, 10, mock.capturedQuery.Limit)
-	assert.Equal(t, "", mock.capturedQuery.Category)
+	assert.Empty(t, mock.capturedQuery.Categories)
 	assert.Nil(t, mock.capturedQuery.PriceLessThan)
 
 	var payload Response
@@ -76,7 +183,7 @@ func TestCatalogHandleGetFiltersAndPagination(t *testing.T) {
 	t.Parallel()
 
 	mock := &productsReaderMock{}
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog?offset=3&limit=250&category=Shoes&price_lt=12.50", nil)
 	res := httptest.NewRecorder()
 
@@ -85,17 +192,52 @@ func TestCatalogHandleGetFiltersAndPagination(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.Equal(t, 3, mock.capturedQuery.Offset)
 	assert.Equal(t, 100, mock.capturedQuery.Limit)
-	assert.Equal(t, "Shoes", mock.capturedQuery.Category)
+	assert.Equal(t, []string{"Shoes"}, mock.capturedQuery.Categories)
 	assert.NotNil(t, mock.capturedQuery.PriceLessThan)
 	assert.True(t, decimal.RequireFromString("12.50").Equal(*mock.capturedQuery.PriceLessThan))
 }
 
+func TestCatalogHandleGetInStockFilter(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		status int
+		want   *bool
+	}{
+		"no filter": {target: "/catalog", status: http.StatusOK},
+		"in stock":  {target: "/catalog?in_stock=true", status: http.StatusOK, want: boolPtr(true)},
+		"sold out":  {target: "/catalog?in_stock=false", status: http.StatusOK, want: boolPtr(false)},
+		"invalid":   {target: "/catalog?in_stock=maybe", status: http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.want, mock.capturedQuery.InStock)
+		})
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
 func TestCatalogHandleGetLimitAndOffsetEdgeCases(t *testing.T) {
 	t.Parallel()
 
 	t.Run("invalid values fallback to defaults", func(t *testing.T) {
 		mock := &productsReaderMock{}
-		handler := NewCatalogHandler(mock)
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 		req := httptest.NewRequest(http.MethodGet, "/catalog?offset=abc&limit=abc", nil)
 		res := httptest.NewRecorder()
 
@@ -108,7 +250,7 @@ func TestCatalogHandleGetLimitAndOffsetEdgeCases(t *testing.T) {
 
 	t.Run("negative offset and low limit are clamped", func(t *testing.T) {
 		mock := &productsReaderMock{}
-		handler := NewCatalogHandler(mock)
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 		req := httptest.NewRequest(http.MethodGet, "/catalog?offset=-5&limit=0", nil)
 		res := httptest.NewRecorder()
 
@@ -124,7 +266,7 @@ func TestCatalogHandleGetInvalidPriceLt(t *testing.T) {
 	t.Parallel()
 
 	mock := &productsReaderMock{}
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog?price_lt=invalid", nil)
 	res := httptest.NewRecorder()
 
@@ -137,7 +279,7 @@ func TestCatalogHandleGetRepositoryError(t *testing.T) {
 	t.Parallel()
 
 	mock := &productsReaderMock{err: errors.New("db failed")}
-	handler := NewCatalogHandler(mock)
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
 	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
 	res := httptest.NewRecorder()
 
@@ -145,3 +287,599 @@ func TestCatalogHandleGetRepositoryError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.Code)
 }
+
+func TestCatalogHandleGetPassesRequestContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "request"))
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	require.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedCtx)
+	assert.Equal(t, "request", mock.capturedCtx.Value(ctxKey{}))
+}
+
+func TestCatalogHandleGetQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{err: fmt.Errorf("count products failed: %w", context.DeadlineExceeded)}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, res.Code)
+	assert.JSONEq(t, `{"type":"urn:problem:timeout","title":"Timeout","status":504,"code":"timeout","detail":"request timed out","instance":"/catalog"}`, res.Body.String())
+}
+
+func TestCatalogHandleGetCursorPagination(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{
+		products: []models.Product{
+			{ID: 3, Code: "PROD003", Price: decimal.RequireFromString("8.75")},
+			{ID: 4, Code: "PROD004", Price: decimal.RequireFromString("15.00")},
+		},
+		hasMore: true,
+	}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?cursor=&limit=2", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Nil(t, mock.capturedAfter)
+	assert.Equal(t, 2, mock.capturedQuery.Limit)
+
+	var payload CursorResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Len(t, payload.Products, 2)
+	require.NotEmpty(t, payload.NextCursor)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?limit=2&cursor="+payload.NextCursor, nil)
+	res = httptest.NewRecorder()
+	mock.hasMore = false
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedAfter)
+	assert.Equal(t, uint(4), mock.capturedAfter.ID)
+
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Empty(t, payload.NextCursor)
+}
+
+func TestCatalogHandleGetInvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	for _, cursor := range []string{"not-base64!", "bm90LWpzb24", "eyJpZCI6MH0"} {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodGet, "/catalog?cursor="+cursor, nil)
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, cursor)
+	}
+}
+
+func TestCatalogHandleGetSort(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		field models.ProductSortField
+		desc  bool
+	}{
+		"":            {field: models.SortByID},
+		"price":       {field: models.SortByPrice},
+		"-price":      {field: models.SortByPrice, desc: true},
+		"code":        {field: models.SortByCode},
+		"-created_at": {field: models.SortByCreatedAt, desc: true},
+	}
+
+	for sort, want := range cases {
+		mock := &productsReaderMock{}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodGet, "/catalog?sort="+sort, nil)
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code, sort)
+		assert.Equal(t, want.field, mock.capturedQuery.SortBy, sort)
+		assert.Equal(t, want.desc, mock.capturedQuery.SortDesc, sort)
+	}
+}
+
+func TestCatalogHandleGetInvalidSort(t *testing.T) {
+	t.Parallel()
+
+	for _, sort := range []string{"name", "--price", "id", "PRICE"} {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodGet, "/catalog?sort="+sort, nil)
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, sort)
+		assert.Contains(t, res.Body.String(), "invalid query parameter: sort")
+	}
+}
+
+func TestCatalogHandleGetCursorKeepsSortValue(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{
+		products: []models.Product{{ID: 5, Code: "PROD005", Price: decimal.RequireFromString("20"), SortPrice: decimal.RequireFromString("22.99")}},
+		hasMore:  true,
+	}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?sort=-price&cursor=", nil)
+	res := httptest.NewRecorder()
+	handler.HandleGet(res, req)
+
+	var payload CursorResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	require.NotEmpty(t, payload.NextCursor)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?sort=-price&cursor="+payload.NextCursor, nil)
+	res = httptest.NewRecorder()
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedAfter)
+	assert.Equal(t, uint(5), mock.capturedAfter.ID)
+	assert.True(t, decimal.RequireFromString("22.99").Equal(mock.capturedAfter.Value.(decimal.Decimal)))
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?sort=code&cursor="+payload.NextCursor, nil)
+	res = httptest.NewRecorder()
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?sort=-price&currency=USD&cursor="+payload.NextCursor, nil)
+	res = httptest.NewRecorder()
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestCatalogHandleGetPriceRange(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?price_gte=5&price_lte=10&price_gt=4&price_match=variant", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedQuery.PriceGreaterThanOrEqual)
+	require.NotNil(t, mock.capturedQuery.PriceLessThanOrEqual)
+	require.NotNil(t, mock.capturedQuery.PriceGreaterThan)
+	assert.Nil(t, mock.capturedQuery.PriceLessThan)
+	assert.True(t, decimal.NewFromInt(5).Equal(*mock.capturedQuery.PriceGreaterThanOrEqual))
+	assert.True(t, decimal.NewFromInt(10).Equal(*mock.capturedQuery.PriceLessThanOrEqual))
+	assert.True(t, mock.capturedQuery.MatchVariantPrices)
+}
+
+func TestCatalogHandleGetInvalidPriceFilters(t *testing.T) {
+	t.Parallel()
+
+	for _, param := range []string{"price_gt=abc", "price_gte=1,5", "price_lte=x", "price_match=cheapest"} {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodGet, "/catalog?"+param, nil)
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code, param)
+	}
+}
+
+func TestCatalogHandleGetListFilters(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?category=SHOES,%20ACCESSORIES&category=Bags&exclude_category=CLOTHING&codes=PROD001,,PROD003", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, []string{"SHOES", "ACCESSORIES", "Bags"}, mock.capturedQuery.Categories)
+	assert.Equal(t, []string{"CLOTHING"}, mock.capturedQuery.ExcludeCategories)
+	assert.Equal(t, []string{"PROD001", "PROD003"}, mock.capturedQuery.Codes)
+}
+
+func TestCatalogHandleSearch(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{
+		products: []models.Product{{Code: "PROD002", Name: "Leather Ankle Boots", Price: decimal.RequireFromString("12.49")}},
+		total:    1,
+	}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog/search?q=leather+boots&category=SHOES&price_lt=20&limit=5", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleSearch(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "leather boots", mock.capturedText)
+	assert.Equal(t, []string{"SHOES"}, mock.capturedQuery.Categories)
+	assert.Equal(t, 5, mock.capturedQuery.Limit)
+	require.NotNil(t, mock.capturedQuery.PriceLessThan)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, int64(1), payload.Total)
+	assert.Equal(t, "Leather Ankle Boots", payload.Products[0].Name)
+}
+
+func TestCatalogHandleSearchErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		err    error
+		status int
+	}{
+		"missing q":      {target: "/catalog/search?q=%20", status: http.StatusBadRequest},
+		"invalid filter": {target: "/catalog/search?q=boots&price_gt=abc", status: http.StatusBadRequest},
+		"repository":     {target: "/catalog/search?q=boots", err: errors.New("db failed"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewCatalogHandler(&productsReaderMock{err: tc.err}, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			res := httptest.NewRecorder()
+
+			handler.HandleSearch(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestCatalogHandleGetFacets(t *testing.T) {
+	t.Parallel()
+
+	ten := decimal.NewFromInt(10)
+	mock := &productsReaderMock{
+		total: 2,
+		facets: &models.ProductFacets{
+			Categories: []models.CategoryFacet{{Code: "SHOES", Name: "Shoes", Count: 2}},
+			Prices: []models.PriceFacet{
+				{Max: &ten, Count: 1},
+				{Min: &ten, Count: 1},
+			},
+		},
+	}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?category=SHOES&facets=category,price", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedFacets)
+	assert.True(t, mock.capturedFacets.Categories)
+	assert.NotEmpty(t, mock.capturedFacets.PriceBoundaries)
+	assert.Equal(t, []string{"SHOES"}, mock.capturedQuery.Categories)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	require.NotNil(t, payload.Facets)
+	assert.Equal(t, int64(2), payload.Facets.Categories[0].Count)
+	require.Len(t, payload.Facets.Prices, 2)
+	assert.Nil(t, payload.Facets.Prices[0].Min)
+	assert.Equal(t, "10", payload.Facets.Prices[0].Max.Amount.String())
+	assert.Equal(t, "10", payload.Facets.Prices[1].Min.Amount.String())
+	assert.Nil(t, payload.Facets.Prices[1].Max)
+}
+
+func TestCatalogHandleGetExactPriceFacets(t *testing.T) {
+	t.Parallel()
+
+	ten := decimal.NewFromInt(10)
+	mock := &productsReaderMock{facets: &models.ProductFacets{Prices: []models.PriceFacet{{Max: &ten, Count: 1}, {Min: &ten}}}}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?facets=price", nil)
+	req.Header.Set("Accept", api.MediaTypeV2)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, api.MediaTypeV2, res.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"Prefer", "Accept"}, res.Header().Values("Vary"))
+	assert.Contains(t, res.Body.String(), `"max":{"amount":"10.00","currency":"EUR"}`)
+}
+
+func TestCatalogHandleGetFacetsNotRequested(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Nil(t, mock.capturedFacets)
+	assert.NotContains(t, res.Body.String(), "facets")
+}
+
+func TestCatalogHandleGetFacetsErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		err    error
+		status int
+	}{
+		"unknown facet": {target: "/catalog?facets=color", status: http.StatusBadRequest},
+		"repository":    {target: "/catalog?facets=price", err: errors.New("db failed"), status: http.StatusInternalServerError},
+		"cursor mode":   {target: "/catalog?cursor=&facets=category", err: errors.New("db failed"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewCatalogHandler(&productsReaderMock{facetsErr: tc.err}, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestCatalogHandleGetCurrencyConversion(t *testing.T) {
+	t.Parallel()
+
+	rates := &exchangeRatesMock{rates: map[string]decimal.Decimal{"EURGBP": decimal.RequireFromString("0.85")}}
+	mock := &productsReaderMock{
+		products: []models.Product{
+			{Code: "PROD001", Price: decimal.RequireFromString("10.99"), Currency: "EUR"},
+			{Code: "PROD002", Price: decimal.RequireFromString("12.49")},
+			{Code: "PROD003", Price: decimal.RequireFromString("7.00"), Currency: "GBP"},
+		},
+		total: 3,
+	}
+	handler := NewCatalogHandler(mock, rates, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?currency=gbp", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, 1, rates.calls)
+	assert.Equal(t, "GBP", mock.capturedQuery.PriceCurrency)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "9.34", payload.Products[0].Price.Amount.String())
+	assert.Equal(t, "GBP", payload.Products[0].Currency)
+	assert.Equal(t, "10.62", payload.Products[1].Price.Amount.String())
+	assert.Equal(t, "7", payload.Products[2].Price.Amount.String())
+	assert.Equal(t, "GBP", payload.Products[2].Currency)
+}
+
+func TestCatalogHandleGetWithoutCurrencyKeepsStoredCurrency(t *testing.T) {
+	t.Parallel()
+
+	rates := &exchangeRatesMock{}
+	mock := &productsReaderMock{products: []models.Product{{Code: "PROD001", Price: decimal.RequireFromString("10.99")}}}
+	handler := NewCatalogHandler(mock, rates, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "EUR", payload.Products[0].Currency)
+	assert.Zero(t, rates.calls)
+}
+
+func TestCatalogHandleGetCurrencyErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		err    error
+		status int
+	}{
+		"malformed currency":  {target: "/catalog?currency=euro", status: http.StatusBadRequest},
+		"unknown currency":    {target: "/catalog?currency=JPY", status: http.StatusBadRequest},
+		"rate lookup failure": {target: "/catalog?currency=GBP", err: errors.New("db failed"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mock := &productsReaderMock{products: []models.Product{{Code: "PROD001", Price: decimal.NewFromInt(1)}}}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{err: tc.err}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestCatalogHandleGetMoneyFormats(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		accept string
+		want   string
+	}{
+		"legacy float":        {target: "/catalog", want: `"price":19.9,`},
+		"string query":        {target: "/catalog?money=string", want: `"price":"19.90",`},
+		"object query":        {target: "/catalog?money=object", want: `"price":{"amount":"19.90","currency":"EUR"},`},
+		"v2 accept header":    {target: "/catalog", accept: "application/vnd.catalog.v2+json", want: `"price":{"amount":"19.90","currency":"EUR"},`},
+		"query beats header":  {target: "/catalog?money=float", accept: "application/vnd.catalog.v2+json", want: `"price":19.9,`},
+		"plain json accepted": {target: "/catalog", accept: "application/json", want: `"price":19.9,`},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{products: []models.Product{{Code: "PROD001", Price: decimal.RequireFromString("19.90")}}, total: 1}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Contains(t, res.Body.String(), tc.want)
+		})
+	}
+}
+
+func TestCatalogHandleGetInvalidMoneyFormat(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?money=cents", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "invalid query parameter: money")
+	assert.Zero(t, mock.capturedQuery)
+}
+
+func TestCatalogHandleGetMarketPrices(t *testing.T) {
+	t.Parallel()
+
+	uk := "UK"
+	endsAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := &priceBooksMock{book: models.NewPriceBook(
+		&models.Market{Code: "UK", Currency: "GBP"},
+		[]models.MarketPrice{{ProductID: 1, MarketCode: "UK", Price: decimal.RequireFromString("9.49")}},
+		[]models.SalePrice{
+			{ProductID: 1, MarketCode: &uk, Price: decimal.RequireFromString("7.99"), EndsAt: endsAt},
+			{ProductID: 2, Price: decimal.RequireFromString("9.99"), EndsAt: endsAt},
+		},
+	)}
+	mock := &productsReaderMock{
+		products: []models.Product{
+			{ID: 1, Code: "PROD001", Price: decimal.RequireFromString("10.99")},
+			{ID: 2, Code: "PROD002", Price: decimal.RequireFromString("12.49")},
+		},
+		total: 2,
+	}
+	rates := &exchangeRatesMock{rates: map[string]decimal.Decimal{"EURGBP": decimal.RequireFromString("0.85")}}
+	handler := NewCatalogHandler(mock, rates, prices)
+	req := httptest.NewRequest(http.MethodGet, "/catalog?market=uk&at=2026-06-01T00:00:00Z", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "UK", prices.capturedMarket)
+	assert.Equal(t, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), prices.capturedAt)
+	assert.Equal(t, []uint{1, 2}, prices.capturedIDs)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "GBP", payload.Products[0].Currency)
+	assert.Equal(t, "7.99", payload.Products[0].Price.Amount.String())
+	require.NotNil(t, payload.Products[0].OriginalPrice)
+	assert.Equal(t, "9.49", payload.Products[0].OriginalPrice.Amount.String())
+	require.NotNil(t, payload.Products[0].SaleEndsAt)
+	assert.True(t, endsAt.Equal(*payload.Products[0].SaleEndsAt))
+
+	assert.Equal(t, "GBP", payload.Products[1].Currency)
+	assert.Equal(t, "8.49", payload.Products[1].Price.Amount.String())
+	require.NotNil(t, payload.Products[1].OriginalPrice)
+	assert.Equal(t, "10.62", payload.Products[1].OriginalPrice.Amount.String())
+}
+
+func TestCatalogHandleGetMarketRejectsPriceQueries(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{facets: &models.ProductFacets{}}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?market=UK&price_lt=10&sort=-price&facets=category,price", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Zero(t, mock.capturedQuery)
+
+	var problem api.Problem
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 3)
+	assert.Equal(t, "price_lt", problem.Errors[0].Field)
+	assert.Equal(t, "sort", problem.Errors[1].Field)
+	assert.Equal(t, "facets", problem.Errors[2].Field)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?market=UK&facets=category&sort=code", nil)
+	res = httptest.NewRecorder()
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestCatalogHandleGetPriceOptionErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		err    error
+		status int
+		body   string
+	}{
+		"malformed market":  {target: "/catalog?market=GBR", status: http.StatusBadRequest, body: "invalid query parameter: market"},
+		"malformed at":      {target: "/catalog?at=tomorrow", status: http.StatusBadRequest, body: "invalid query parameter: at"},
+		"unknown market":    {target: "/catalog?market=FR", err: models.ErrMarketNotFound, status: http.StatusBadRequest, body: "unknown market"},
+		"price book failed": {target: "/catalog", err: errors.New("db failed"), status: http.StatusInternalServerError, body: "failed to resolve prices"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{products: []models.Product{{ID: 1, Code: "PROD001"}}, total: 1}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{err: tc.err})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+			assert.Contains(t, res.Body.String(), tc.body)
+		})
+	}
+}
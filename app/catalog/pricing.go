@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// PriceBookReader defines the price list lookups consumed by catalog handlers.
+type PriceBookReader interface {
+	GetPriceBook(ctx context.Context, market string, at time.Time, productIDs []uint) (*models.PriceBook, error)
+}
+
+// priceOptions describe which prices a response shows and how they are presented.
+type priceOptions struct {
+	market    string
+	at        time.Time
+	converter *priceConverter
+	format    api.MoneyFormat
+}
+
+// parsePriceOptions reads the market, point in time, currency and money format of a request.
+// Prices are resolved at the current time unless at holds an RFC 3339 timestamp.
+// It returns the name of the invalid query parameter, or an empty string.
+func (h *CatalogHandler) parsePriceOptions(w http.ResponseWriter, r *http.Request) (priceOptions, string) {
+	query := r.URL.Query()
+
+	var market string
+	if raw := query.Get("market"); raw != "" {
+		normalized, ok := models.NormalizeMarketCode(raw)
+		if !ok {
+			return priceOptions{}, "market"
+		}
+		market = normalized
+	}
+
+	at := time.Now()
+	if raw := query.Get("at"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return priceOptions{}, "at"
+		}
+		at = parsed
+	}
+
+	currency, ok := parseCurrency(query.Get("currency"))
+	if !ok {
+		return priceOptions{}, "currency"
+	}
+
+	format, ok := api.MoneyFormatFromRequest(w, r)
+	if !ok {
+		return priceOptions{}, "money"
+	}
+
+	return priceOptions{
+		market:    market,
+		at:        at,
+		converter: newPriceConverter(h.rates, currency),
+		format:    format,
+	}, ""
+}
+
+// resolvePrices returns the prices of the products in the requested market and currency,
+// in the order of the products. Without a requested currency, the prices of products missing from
+// the market's price list are converted into the market currency.
+func (h *CatalogHandler) resolvePrices(ctx context.Context, products []models.Product, prices priceOptions) ([]models.ProductPricing, error) {
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	book, err := h.priceBooks.GetPriceBook(ctx, prices.market, prices.at, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := h.marketConverter(prices, book)
+	resolved := make([]models.ProductPricing, len(products))
+	for i, product := range products {
+		pricing, err := converter.convertPricing(ctx, book.Resolve(product))
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = pricing
+	}
+
+	return resolved, nil
+}
+
+// marketConverter returns the converter of the requested currency, or of the market currency when
+// prices are resolved for a market without a requested currency.
+func (h *CatalogHandler) marketConverter(prices priceOptions, book *models.PriceBook) *priceConverter {
+	if prices.converter != nil || book == nil || book.Market == nil {
+		return prices.converter
+	}
+
+	return newPriceConverter(h.rates, book.Market.Currency)
+}
+
+// marketPriceConflicts returns an error for each price filter, price sort order and price facet of a
+// request for a market. These compare stored prices, converted into one currency, and so would not
+// match the market price list and its sales.
+func marketPriceConflicts(query url.Values, filter models.ProductCatalogFilter, facets *models.FacetRequest) []api.FieldError {
+	if query.Get("market") == "" {
+		return nil
+	}
+
+	var errs []api.FieldError
+	for _, name := range []string{"price_lt", "price_lte", "price_gt", "price_gte"} {
+		if query.Get(name) != "" {
+			errs = append(errs, marketConflict(name))
+		}
+	}
+
+	if filter.SortBy == models.SortByPrice {
+		errs = append(errs, marketConflict("sort"))
+	}
+
+	if facets != nil && len(facets.PriceBoundaries) > 0 {
+		errs = append(errs, marketConflict("facets"))
+	}
+
+	return errs
+}
+
+func marketConflict(name string) api.FieldError {
+	return api.FieldError{Field: name, Code: api.FieldInvalid, Message: name + " on prices cannot be combined with market"}
+}
+
+// salePrice returns the original price and sale end of a discounted price, or nils without a sale.
+func salePrice(price models.ResolvedPrice, currency string, format api.MoneyFormat) (*api.Money, *time.Time) {
+	if price.Sale == nil {
+		return nil, nil
+	}
+
+	original := api.NewMoney(price.Original, currency, format)
+	return &original, price.SaleEndsAt
+}
@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// reservationSweepBatchSize is the number of expired reservations released per transaction.
+const reservationSweepBatchSize = 100
+
+// ExpiredReservationReleaser defines the operation consumed by the reservation sweeper.
+type ExpiredReservationReleaser interface {
+	ReleaseExpiredReservations(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+// ReservationSweeper periodically releases expired stock reservations.
+type ReservationSweeper struct {
+	repo     ExpiredReservationReleaser
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewReservationSweeper creates a sweeper releasing expired reservations every interval.
+// It logs released reservations and failed sweeps to logger.
+func NewReservationSweeper(repo ExpiredReservationReleaser, interval time.Duration, logger *slog.Logger) *ReservationSweeper {
+	return &ReservationSweeper{repo: repo, interval: interval, logger: logger, now: time.Now}
+}
+
+// Run sweeps expired reservations until ctx is done.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// sweep releases expired reservations in batches until a batch comes back short.
+func (s *ReservationSweeper) sweep(ctx context.Context) {
+	now := s.now()
+	for ctx.Err() == nil {
+		released, err := s.repo.ReleaseExpiredReservations(ctx, now, reservationSweepBatchSize)
+		if err != nil {
+			s.logger.LogAttrs(ctx, slog.LevelError, "releasing expired reservations failed", slog.String("error", err.Error()))
+			return
+		}
+
+		if released > 0 {
+			s.logger.LogAttrs(ctx, slog.LevelInfo, "released expired reservations", slog.Int("count", released))
+		}
+
+		if released < reservationSweepBatchSize {
+			return
+		}
+	}
+}
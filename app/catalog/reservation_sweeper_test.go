@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type expiredReservationsMock struct {
+	mu       sync.Mutex
+	released []int
+	err      error
+	calls    int
+	limits   []int
+}
+
+func (m *expiredReservationsMock) ReleaseExpiredReservations(_ context.Context, _ time.Time, limit int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+	m.limits = append(m.limits, limit)
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	if len(m.released) == 0 {
+		return 0, nil
+	}
+
+	released := m.released[0]
+	m.released = m.released[1:]
+	return released, nil
+}
+
+func TestReservationSweeperSweepsInBatches(t *testing.T) {
+	t.Parallel()
+
+	mock := &expiredReservationsMock{released: []int{reservationSweepBatchSize, 3}}
+	sweeper := NewReservationSweeper(mock, time.Minute, slog.New(slog.DiscardHandler))
+
+	sweeper.sweep(context.Background())
+
+	assert.Equal(t, 2, mock.calls)
+	assert.Equal(t, []int{reservationSweepBatchSize, reservationSweepBatchSize}, mock.limits)
+}
+
+func TestReservationSweeperStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	mock := &expiredReservationsMock{err: errors.New("db failed")}
+	sweeper := NewReservationSweeper(mock, time.Minute, slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	sweeper.sweep(context.Background())
+
+	assert.Equal(t, 1, mock.calls)
+	assert.Contains(t, logs.String(), `"level":"ERROR","msg":"releasing expired reservations failed","error":"db failed"`)
+}
+
+func TestReservationSweeperRunStopsWithContext(t *testing.T) {
+	t.Parallel()
+
+	mock := &expiredReservationsMock{}
+	sweeper := NewReservationSweeper(mock, time.Millisecond, slog.New(slog.DiscardHandler))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		sweeper.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		return mock.calls > 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,180 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 24 * time.Hour
+	maxCartIDLength       = 64
+)
+
+// ReservationReaderWriter defines reservation operations consumed by the reservations handler.
+type ReservationReaderWriter interface {
+	CreateReservation(ctx context.Context, input models.ReservationInput) (*models.StockReservation, error)
+	GetReservation(ctx context.Context, id string) (*models.StockReservation, error)
+	CommitReservation(ctx context.Context, id string) (*models.StockReservation, error)
+	ReleaseReservation(ctx context.Context, id string) (*models.StockReservation, error)
+}
+
+// ReservationsHandler exposes HTTP handlers for checkout stock reservations.
+type ReservationsHandler struct {
+	repo ReservationReaderWriter
+}
+
+// NewReservationsHandler creates a new ReservationsHandler.
+func NewReservationsHandler(repo ReservationReaderWriter) *ReservationsHandler {
+	return &ReservationsHandler{repo: repo}
+}
+
+// CreateReservationRequest represents a reservation payload.
+// An empty warehouse reserves in the default warehouse and a zero ttl_seconds holds for 15 minutes.
+type CreateReservationRequest struct {
+	SKU        string `json:"sku"`
+	Warehouse  string `json:"warehouse"`
+	Quantity   int    `json:"quantity"`
+	CartID     string `json:"cart_id"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
+
+// ReservationResponse represents a stock reservation.
+type ReservationResponse struct {
+	ID        string    `json:"id"`
+	SKU       string    `json:"sku"`
+	Warehouse string    `json:"warehouse"`
+	Quantity  int       `json:"quantity"`
+	CartID    string    `json:"cart_id"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// HandlePost validates and creates a reservation.
+func (h *ReservationsHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	req.SKU = strings.TrimSpace(req.SKU)
+	req.CartID = strings.TrimSpace(req.CartID)
+	var missing api.FieldErrors
+	missing.Require("sku", req.SKU != "")
+	missing.Require("cart_id", req.CartID != "")
+	if len(missing) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, missing...)
+		return
+	}
+
+	if utf8.RuneCountInString(req.CartID) > maxCartIDLength {
+		api.InvalidFieldResponse(w, r, "cart_id", api.FieldTooLong, "cart_id is too long")
+		return
+	}
+
+	warehouse, ok := parseWarehouse(req.Warehouse)
+	if !ok {
+		api.InvalidFieldResponse(w, r, "warehouse", api.FieldTooLong, "warehouse is too long")
+		return
+	}
+
+	if req.Quantity <= 0 {
+		api.InvalidFieldResponse(w, r, "quantity", api.FieldInvalid, "quantity must be positive")
+		return
+	}
+
+	if req.TTLSeconds < 0 || req.TTLSeconds > int(maxReservationTTL/time.Second) {
+		api.InvalidFieldResponse(w, r, "ttl_seconds", api.FieldInvalid, "ttl_seconds must be between 1 and 86400")
+		return
+	}
+
+	ttl := defaultReservationTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	reservation, err := h.repo.CreateReservation(r.Context(), models.ReservationInput{
+		SKU:       req.SKU,
+		Warehouse: warehouse,
+		Quantity:  req.Quantity,
+		CartID:    req.CartID,
+		TTL:       ttl,
+	})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to create reservation")
+		return
+	}
+
+	api.CreatedResponse(w, toReservationResponse(*reservation))
+}
+
+// HandleGet returns a reservation by id.
+func (h *ReservationsHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing reservation id")
+		return
+	}
+
+	reservation, err := h.repo.GetReservation(r.Context(), id)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch reservation")
+		return
+	}
+
+	api.OKResponse(w, toReservationResponse(*reservation))
+}
+
+// HandleCommit commits a held reservation, removing its quantity from stock.
+func (h *ReservationsHandler) HandleCommit(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing reservation id")
+		return
+	}
+
+	reservation, err := h.repo.CommitReservation(r.Context(), id)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to commit reservation")
+		return
+	}
+
+	api.OKResponse(w, toReservationResponse(*reservation))
+}
+
+// HandleRelease releases a held reservation, making its quantity available again.
+func (h *ReservationsHandler) HandleRelease(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing reservation id")
+		return
+	}
+
+	reservation, err := h.repo.ReleaseReservation(r.Context(), id)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to release reservation")
+		return
+	}
+
+	api.OKResponse(w, toReservationResponse(*reservation))
+}
+
+func toReservationResponse(reservation models.StockReservation) ReservationResponse {
+	return ReservationResponse{
+		ID:        reservation.ID,
+		SKU:       reservation.Variant.SKU,
+		Warehouse: reservation.Warehouse,
+		Quantity:  reservation.Quantity,
+		CartID:    reservation.CartID,
+		Status:    string(reservation.Status),
+		ExpiresAt: reservation.ExpiresAt,
+	}
+}
@@ -0,0 +1,205 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reservationsRepoMock struct {
+	err           error
+	capturedInput models.ReservationInput
+	capturedID    string
+}
+
+func (m *reservationsRepoMock) CreateReservation(_ context.Context, input models.ReservationInput) (*models.StockReservation, error) {
+	m.capturedInput = input
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &models.StockReservation{
+		ID:        "res-1",
+		Variant:   models.Variant{SKU: input.SKU},
+		Warehouse: input.Warehouse,
+		Quantity:  input.Quantity,
+		CartID:    input.CartID,
+		Status:    models.ReservationHeld,
+		ExpiresAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *reservationsRepoMock) GetReservation(_ context.Context, id string) (*models.StockReservation, error) {
+	return m.reservation(id, models.ReservationHeld)
+}
+
+func (m *reservationsRepoMock) CommitReservation(_ context.Context, id string) (*models.StockReservation, error) {
+	return m.reservation(id, models.ReservationCommitted)
+}
+
+func (m *reservationsRepoMock) ReleaseReservation(_ context.Context, id string) (*models.StockReservation, error) {
+	return m.reservation(id, models.ReservationReleased)
+}
+
+func (m *reservationsRepoMock) reservation(id string, status models.ReservationStatus) (*models.StockReservation, error) {
+	m.capturedID = id
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &models.StockReservation{ID: id, Variant: models.Variant{SKU: "SKU001A"}, Quantity: 1, Status: status}, nil
+}
+
+func newReservationRequest(method, target, id, body string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	if id != "" {
+		req.SetPathValue("id", id)
+	}
+	return req
+}
+
+func TestReservationsHandlePost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates reservation with defaults", func(t *testing.T) {
+		mock := &reservationsRepoMock{}
+		handler := NewReservationsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newReservationRequest(http.MethodPost, "/reservations", "", `{"sku":" SKU001A ","quantity":2,"cart_id":"cart-1"}`))
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, models.ReservationInput{
+			SKU:       "SKU001A",
+			Warehouse: models.DefaultWarehouse,
+			Quantity:  2,
+			CartID:    "cart-1",
+			TTL:       15 * time.Minute,
+		}, mock.capturedInput)
+
+		var payload ReservationResponse
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.Equal(t, "res-1", payload.ID)
+		assert.Equal(t, "SKU001A", payload.SKU)
+		assert.Equal(t, "held", payload.Status)
+	})
+
+	t.Run("custom ttl", func(t *testing.T) {
+		mock := &reservationsRepoMock{}
+		handler := NewReservationsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newReservationRequest(http.MethodPost, "/reservations", "", `{"sku":"SKU001A","warehouse":"berlin","quantity":1,"cart_id":"cart-1","ttl_seconds":60}`))
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, time.Minute, mock.capturedInput.TTL)
+		assert.Equal(t, "berlin", mock.capturedInput.Warehouse)
+	})
+
+	t.Run("multibyte cart id", func(t *testing.T) {
+		mock := &reservationsRepoMock{}
+		handler := NewReservationsHandler(mock)
+		cartID := strings.Repeat("Ä", maxCartIDLength)
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newReservationRequest(http.MethodPost, "/reservations", "", `{"sku":"SKU001A","quantity":1,"cart_id":"`+cartID+`"}`))
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, cartID, mock.capturedInput.CartID)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		bodies := []string{
+			`{"sku":`,
+			`{"sku":"SKU001A","quantity":1}`,
+			`{"sku":"SKU001A","quantity":0,"cart_id":"cart-1"}`,
+			`{"sku":"SKU001A","quantity":1,"cart_id":"cart-1","ttl_seconds":-5}`,
+			`{"sku":"SKU001A","quantity":1,"cart_id":"cart-1","ttl_seconds":86401}`,
+			`{"sku":"SKU001A","quantity":1,"cart_id":"cart-1","ttl_seconds":9223372036854775807}`,
+			`{"sku":"SKU001A","quantity":1,"cart_id":"` + strings.Repeat("c", maxCartIDLength+1) + `"}`,
+		}
+
+		for _, body := range bodies {
+			handler := NewReservationsHandler(&reservationsRepoMock{})
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, newReservationRequest(http.MethodPost, "/reservations", "", body))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		cases := map[error]int{
+			models.ErrVariantNotFound:   http.StatusNotFound,
+			models.ErrInsufficientStock: http.StatusConflict,
+			errors.New("db failed"):     http.StatusInternalServerError,
+		}
+
+		for err, status := range cases {
+			handler := NewReservationsHandler(&reservationsRepoMock{err: err})
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, newReservationRequest(http.MethodPost, "/reservations", "", `{"sku":"SKU001A","quantity":1,"cart_id":"cart-1"}`))
+
+			assert.Equal(t, status, res.Code, err.Error())
+		}
+	})
+}
+
+func TestReservationsHandleLifecycle(t *testing.T) {
+	t.Parallel()
+
+	mock := &reservationsRepoMock{}
+	handler := NewReservationsHandler(mock)
+
+	res := httptest.NewRecorder()
+	handler.HandleGet(res, newReservationRequest(http.MethodGet, "/reservations/res-1", "res-1", ""))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "res-1", mock.capturedID)
+
+	res = httptest.NewRecorder()
+	handler.HandleCommit(res, newReservationRequest(http.MethodPost, "/reservations/res-1/commit", "res-1", ""))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"status":"committed"`)
+
+	res = httptest.NewRecorder()
+	handler.HandleRelease(res, newReservationRequest(http.MethodPost, "/reservations/res-1/release", "res-1", ""))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"status":"released"`)
+}
+
+func TestReservationsHandleCommitErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[error]int{
+		models.ErrReservationNotFound: http.StatusNotFound,
+		models.ErrReservationNotHeld:  http.StatusConflict,
+		models.ErrReservationExpired:  http.StatusGone,
+		errors.New("db failed"):       http.StatusInternalServerError,
+	}
+
+	for err, status := range cases {
+		handler := NewReservationsHandler(&reservationsRepoMock{err: err})
+		res := httptest.NewRecorder()
+
+		handler.HandleCommit(res, newReservationRequest(http.MethodPost, "/reservations/res-1/commit", "res-1", ""))
+
+		assert.Equal(t, status, res.Code, err.Error())
+	}
+
+	handler := NewReservationsHandler(&reservationsRepoMock{})
+	res := httptest.NewRecorder()
+	handler.HandleCommit(res, newReservationRequest(http.MethodPost, "/reservations//commit", "", ""))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
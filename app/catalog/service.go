@@ -1,6 +1,9 @@
 package catalog
 
-import "github.com/mytheresa/go-hiring-challenge/models"
+import (
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
 
 type detailsService struct{}
 
@@ -8,24 +11,39 @@ func newDetailsService() *detailsService {
 	return &detailsService{}
 }
 
-func (s *detailsService) BuildProductDetails(product *models.Product) ProductDetailsResponse {
+// BuildProductDetails maps a product and its resolved prices to the details response.
+func (s *detailsService) BuildProductDetails(
+	product *models.Product,
+	pricing models.ProductPricing,
+	format api.MoneyFormat,
+) ProductDetailsResponse {
+	currency := pricing.Currency
 	variants := make([]ProductVariant, len(product.Variants))
 	for i, variant := range product.Variants {
-		price := product.Price
-		if variant.Price != nil {
-			price = *variant.Price
-		}
-
+		price := pricing.Variants[i]
+		originalPrice, saleEndsAt := salePrice(price, currency, format)
+		stock := variant.AvailableQuantity()
 		variants[i] = ProductVariant{
-			Name:  variant.Name,
-			SKU:   variant.SKU,
-			Price: price.InexactFloat64(),
+			Name:          variant.Name,
+			SKU:           variant.SKU,
+			Price:         api.NewMoney(price.Active(), currency, format),
+			OriginalPrice: originalPrice,
+			SaleEndsAt:    saleEndsAt,
+			Currency:      currency,
+			Stock:         stock,
+			InStock:       stock > 0,
 		}
 	}
 
+	originalPrice, saleEndsAt := salePrice(pricing.Price, currency, format)
 	return ProductDetailsResponse{
-		Code:  product.Code,
-		Price: product.Price.InexactFloat64(),
+		Code:          product.Code,
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         api.NewMoney(pricing.Price.Active(), currency, format),
+		OriginalPrice: originalPrice,
+		SaleEndsAt:    saleEndsAt,
+		Currency:      currency,
 		Category: Category{
 			Code: product.Category.Code,
 			Name: product.Category.Name,
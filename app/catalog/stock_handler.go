@@ -0,0 +1,182 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+const maxWarehouseLength = 32
+
+// StockReaderWriter defines stock operations consumed by the stock handler.
+type StockReaderWriter interface {
+	ListStock(ctx context.Context, productCode, sku string) ([]models.StockLevel, error)
+	AdjustStock(ctx context.Context, productCode, sku string, adjustment models.StockAdjustment) (*models.StockLevel, error)
+	SetStock(ctx context.Context, productCode, sku string, input models.StockInput) (*models.StockLevel, error)
+}
+
+// StockHandler exposes HTTP handlers for variant stock levels.
+type StockHandler struct {
+	repo StockReaderWriter
+}
+
+// NewStockHandler creates a new StockHandler.
+func NewStockHandler(repo StockReaderWriter) *StockHandler {
+	return &StockHandler{repo: repo}
+}
+
+// StockResponse represents the stock of a variant across warehouses.
+// Available excludes the quantity held by reservations.
+type StockResponse struct {
+	SKU        string               `json:"sku"`
+	Total      int                  `json:"total"`
+	Available  int                  `json:"available"`
+	Warehouses []StockLevelResponse `json:"warehouses"`
+}
+
+// StockLevelResponse represents the stock of a variant in a warehouse.
+// Version must be sent back to update the level optimistically.
+type StockLevelResponse struct {
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+	Version   int    `json:"version"`
+}
+
+// AdjustStockRequest represents a relative stock change.
+// An empty warehouse adjusts the default warehouse.
+type AdjustStockRequest struct {
+	Warehouse string `json:"warehouse"`
+	Delta     int    `json:"delta"`
+}
+
+// SetStockRequest represents an absolute stock quantity.
+// When version is present the update fails with a conflict if the level changed in the meantime.
+type SetStockRequest struct {
+	Quantity *int `json:"quantity"`
+	Version  *int `json:"version"`
+}
+
+// HandleGet returns the stock of a variant in every warehouse.
+func (h *StockHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	code, sku := r.PathValue("code"), r.PathValue("sku")
+	if code == "" || sku == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code or sku")
+		return
+	}
+
+	levels, err := h.repo.ListStock(r.Context(), code, sku)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch stock")
+		return
+	}
+
+	response := StockResponse{SKU: sku, Warehouses: make([]StockLevelResponse, len(levels))}
+	for i, level := range levels {
+		response.Warehouses[i] = toStockLevelResponse(level)
+		response.Total += level.Quantity
+		response.Available += level.Available()
+	}
+
+	api.OKResponse(w, response)
+}
+
+// HandleAdjust atomically adds a positive or negative delta to the stock of a variant in a warehouse.
+func (h *StockHandler) HandleAdjust(w http.ResponseWriter, r *http.Request) {
+	code, sku := r.PathValue("code"), r.PathValue("sku")
+	if code == "" || sku == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code or sku")
+		return
+	}
+
+	var req AdjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	warehouse, ok := parseWarehouse(req.Warehouse)
+	if !ok {
+		api.InvalidFieldResponse(w, r, "warehouse", api.FieldTooLong, "warehouse is too long")
+		return
+	}
+
+	if req.Delta == 0 {
+		api.InvalidFieldResponse(w, r, "delta", api.FieldInvalid, "delta must not be zero")
+		return
+	}
+
+	level, err := h.repo.AdjustStock(r.Context(), code, sku, models.StockAdjustment{Warehouse: warehouse, Delta: req.Delta})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to adjust stock")
+		return
+	}
+
+	api.OKResponse(w, toStockLevelResponse(*level))
+}
+
+// HandlePut sets the stock of a variant in the warehouse given in the path.
+func (h *StockHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	code, sku := r.PathValue("code"), r.PathValue("sku")
+	if code == "" || sku == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code or sku")
+		return
+	}
+
+	warehouse, ok := parseWarehouse(r.PathValue("warehouse"))
+	if !ok {
+		api.InvalidFieldResponse(w, r, "warehouse", api.FieldTooLong, "warehouse is too long")
+		return
+	}
+
+	var req SetStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	if req.Quantity == nil {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, api.FieldError{Field: "quantity", Code: api.FieldRequired, Message: "quantity is required"})
+		return
+	}
+
+	if *req.Quantity < 0 {
+		api.InvalidFieldResponse(w, r, "quantity", api.FieldInvalid, "quantity must not be negative")
+		return
+	}
+
+	level, err := h.repo.SetStock(r.Context(), code, sku, models.StockInput{Warehouse: warehouse, Quantity: *req.Quantity, Version: req.Version})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to update stock")
+		return
+	}
+
+	api.OKResponse(w, toStockLevelResponse(*level))
+}
+
+// parseWarehouse trims a warehouse name, defaulting to models.DefaultWarehouse.
+// It reports false when the name is too long.
+func parseWarehouse(raw string) (string, bool) {
+	warehouse := strings.TrimSpace(raw)
+	if warehouse == "" {
+		return models.DefaultWarehouse, true
+	}
+
+	return warehouse, utf8.RuneCountInString(warehouse) <= maxWarehouseLength
+}
+
+func toStockLevelResponse(level models.StockLevel) StockLevelResponse {
+	return StockLevelResponse{
+		Warehouse: level.Warehouse,
+		Quantity:  level.Quantity,
+		Reserved:  level.Reserved,
+		Available: level.Available(),
+		Version:   level.Version,
+	}
+}
@@ -0,0 +1,209 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stockRepoMock struct {
+	levels             []models.StockLevel
+	err                error
+	capturedCode       string
+	capturedSKU        string
+	capturedAdjustment models.StockAdjustment
+	capturedInput      models.StockInput
+}
+
+func (m *stockRepoMock) ListStock(_ context.Context, productCode, sku string) ([]models.StockLevel, error) {
+	m.capturedCode = productCode
+	m.capturedSKU = sku
+	return m.levels, m.err
+}
+
+func (m *stockRepoMock) AdjustStock(_ context.Context, productCode, sku string, adjustment models.StockAdjustment) (*models.StockLevel, error) {
+	m.capturedCode = productCode
+	m.capturedSKU = sku
+	m.capturedAdjustment = adjustment
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &models.StockLevel{Warehouse: adjustment.Warehouse, Quantity: 10 + adjustment.Delta, Version: 1}, nil
+}
+
+func (m *stockRepoMock) SetStock(_ context.Context, productCode, sku string, input models.StockInput) (*models.StockLevel, error) {
+	m.capturedCode = productCode
+	m.capturedSKU = sku
+	m.capturedInput = input
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &models.StockLevel{Warehouse: input.Warehouse, Quantity: input.Quantity, Version: 1}, nil
+}
+
+func newStockRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("sku", "SKU001A")
+	return req
+}
+
+func TestStockHandleGet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sums warehouses", func(t *testing.T) {
+		mock := &stockRepoMock{levels: []models.StockLevel{
+			{Warehouse: "berlin", Quantity: 5, Reserved: 2, Version: 2},
+			{Warehouse: "default", Quantity: 10},
+		}}
+		handler := NewStockHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, newStockRequest(http.MethodGet, "/catalog/PROD001/variants/SKU001A/stock", ""))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "PROD001", mock.capturedCode)
+		assert.Equal(t, "SKU001A", mock.capturedSKU)
+
+		var payload StockResponse
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.Equal(t, "SKU001A", payload.SKU)
+		assert.Equal(t, 15, payload.Total)
+		assert.Equal(t, 13, payload.Available)
+		require.Len(t, payload.Warehouses, 2)
+		assert.Equal(t, StockLevelResponse{Warehouse: "berlin", Quantity: 5, Reserved: 2, Available: 3, Version: 2}, payload.Warehouses[0])
+	})
+
+	t.Run("unknown variant", func(t *testing.T) {
+		handler := NewStockHandler(&stockRepoMock{err: models.ErrVariantNotFound})
+		res := httptest.NewRecorder()
+
+		handler.HandleGet(res, newStockRequest(http.MethodGet, "/catalog/PROD001/variants/SKU001A/stock", ""))
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestStockHandleAdjust(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults warehouse", func(t *testing.T) {
+		mock := &stockRepoMock{}
+		handler := NewStockHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandleAdjust(res, newStockRequest(http.MethodPost, "/catalog/PROD001/variants/SKU001A/stock/adjustments", `{"delta":-3}`))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, models.StockAdjustment{Warehouse: models.DefaultWarehouse, Delta: -3}, mock.capturedAdjustment)
+
+		var payload StockLevelResponse
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.Equal(t, 7, payload.Quantity)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		bodies := []string{
+			`{"delta":`,
+			`{"delta":0}`,
+			`{"warehouse":"a-warehouse-name-longer-than-32-characters","delta":1}`,
+		}
+
+		for _, body := range bodies {
+			handler := NewStockHandler(&stockRepoMock{})
+			res := httptest.NewRecorder()
+
+			handler.HandleAdjust(res, newStockRequest(http.MethodPost, "/catalog/PROD001/variants/SKU001A/stock/adjustments", body))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		cases := map[error]int{
+			models.ErrVariantNotFound:      http.StatusNotFound,
+			models.ErrInsufficientStock:    http.StatusConflict,
+			models.ErrStockVersionConflict: http.StatusConflict,
+			errors.New("db failed"):        http.StatusInternalServerError,
+		}
+
+		for err, status := range cases {
+			handler := NewStockHandler(&stockRepoMock{err: err})
+			res := httptest.NewRecorder()
+
+			handler.HandleAdjust(res, newStockRequest(http.MethodPost, "/catalog/PROD001/variants/SKU001A/stock/adjustments", `{"delta":-20}`))
+
+			assert.Equal(t, status, res.Code, err.Error())
+		}
+	})
+}
+
+func TestStockHandlePut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets quantity with version", func(t *testing.T) {
+		mock := &stockRepoMock{}
+		handler := NewStockHandler(mock)
+		req := newStockRequest(http.MethodPut, "/catalog/PROD001/variants/SKU001A/stock/berlin", `{"quantity":4,"version":2}`)
+		req.SetPathValue("warehouse", "berlin")
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "berlin", mock.capturedInput.Warehouse)
+		assert.Equal(t, 4, mock.capturedInput.Quantity)
+		require.NotNil(t, mock.capturedInput.Version)
+		assert.Equal(t, 2, *mock.capturedInput.Version)
+	})
+
+	t.Run("multibyte warehouse", func(t *testing.T) {
+		mock := &stockRepoMock{}
+		handler := NewStockHandler(mock)
+		warehouse := strings.Repeat("ü", maxWarehouseLength)
+		req := newStockRequest(http.MethodPut, "/catalog/PROD001/variants/SKU001A/stock/munich", `{"quantity":4}`)
+		req.SetPathValue("warehouse", warehouse)
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, warehouse, mock.capturedInput.Warehouse)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		for _, body := range []string{`{`, `{}`, `{"quantity":-1}`} {
+			handler := NewStockHandler(&stockRepoMock{})
+			req := newStockRequest(http.MethodPut, "/catalog/PROD001/variants/SKU001A/stock/berlin", body)
+			req.SetPathValue("warehouse", "berlin")
+			res := httptest.NewRecorder()
+
+			handler.HandlePut(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		handler := NewStockHandler(&stockRepoMock{err: models.ErrStockVersionConflict})
+		req := newStockRequest(http.MethodPut, "/catalog/PROD001/variants/SKU001A/stock/berlin", `{"quantity":4,"version":1}`)
+		req.SetPathValue("warehouse", "berlin")
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, req)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Contains(t, res.Body.String(), "stock version conflict")
+	})
+}
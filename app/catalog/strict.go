@@ -0,0 +1,93 @@
+package catalog
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+)
+
+// Preferences of the Prefer request header (RFC 7240) choosing how query parameters are handled.
+const (
+	preferStrict  = "handling=strict"
+	preferLenient = "handling=lenient"
+)
+
+// catalogQueryParameters are the query parameters accepted by HandleGet.
+var catalogQueryParameters = []string{
+	"offset", "limit", "cursor", "sort", "facets",
+	"category", "exclude_category", "codes",
+	"price_lt", "price_lte", "price_gt", "price_gte", "price_match", "in_stock",
+	"market", "at", "currency", "money",
+}
+
+// SetStrictQuery sets whether HandleGet validates its query parameters strictly by default.
+// Requests override the default with the Prefer header, see isStrict.
+func (h *CatalogHandler) SetStrictQuery(strict bool) {
+	h.strict = strict
+}
+
+// isStrict reports whether the query parameters of r are validated strictly. Prefer: handling=strict
+// or handling=lenient overrides the server default, and the applied preference is echoed in the
+// Preference-Applied response header.
+func (h *CatalogHandler) isStrict(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Add("Vary", "Prefer")
+
+	for _, preference := range strings.Split(r.Header.Get("Prefer"), ",") {
+		preference, _, _ = strings.Cut(preference, ";")
+		switch strings.ToLower(strings.TrimSpace(preference)) {
+		case preferStrict:
+			w.Header().Set("Preference-Applied", preferStrict)
+			return true
+		case preferLenient:
+			w.Header().Set("Preference-Applied", preferLenient)
+			return false
+		}
+	}
+
+	return h.strict
+}
+
+// validateStrictQuery rejects unknown query parameters and the malformed or out-of-range offset and
+// limit values that parseOffset and parseLimit would otherwise replace or clamp.
+func validateStrictQuery(query url.Values) api.FieldErrors {
+	var errs api.FieldErrors
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !slices.Contains(catalogQueryParameters, name) {
+			errs = append(errs, api.FieldError{Field: name, Code: api.FieldUnknown, Message: "unknown query parameter: " + name})
+		}
+	}
+
+	if query.Has("offset") {
+		offset, err := strconv.Atoi(query.Get("offset"))
+		switch {
+		case err != nil:
+			errs = append(errs, api.FieldError{Field: "offset", Code: api.FieldInvalid, Message: "offset must be an integer"})
+		case offset < 0:
+			errs = append(errs, api.FieldError{Field: "offset", Code: api.FieldOutOfRange, Message: "offset must not be negative"})
+		}
+	}
+
+	if query.Has("limit") {
+		limit, err := strconv.Atoi(query.Get("limit"))
+		switch {
+		case err != nil:
+			errs = append(errs, api.FieldError{Field: "limit", Code: api.FieldInvalid, Message: "limit must be an integer"})
+		case limit < 1 || limit > maxLimit:
+			errs = append(errs, api.FieldError{Field: "limit", Code: api.FieldOutOfRange, Message: fmt.Sprintf("limit must be between 1 and %d", maxLimit)})
+		}
+	}
+
+	return errs
+}
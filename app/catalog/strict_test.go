@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogHandleGetStrictQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		errors []api.FieldError
+	}{
+		"malformed offset": {
+			target: "/catalog?offset=abc",
+			errors: []api.FieldError{{Field: "offset", Code: api.FieldInvalid, Message: "offset must be an integer"}},
+		},
+		"negative offset": {
+			target: "/catalog?offset=-5",
+			errors: []api.FieldError{{Field: "offset", Code: api.FieldOutOfRange, Message: "offset must not be negative"}},
+		},
+		"malformed limit": {
+			target: "/catalog?limit=abc",
+			errors: []api.FieldError{{Field: "limit", Code: api.FieldInvalid, Message: "limit must be an integer"}},
+		},
+		"limit too low": {
+			target: "/catalog?limit=0",
+			errors: []api.FieldError{{Field: "limit", Code: api.FieldOutOfRange, Message: "limit must be between 1 and 100"}},
+		},
+		"limit too high": {
+			target: "/catalog?limit=500",
+			errors: []api.FieldError{{Field: "limit", Code: api.FieldOutOfRange, Message: "limit must be between 1 and 100"}},
+		},
+		"unknown parameters": {
+			target: "/catalog?page=2&categroy=SHOES&offset=-1",
+			errors: []api.FieldError{
+				{Field: "categroy", Code: api.FieldUnknown, Message: "unknown query parameter: categroy"},
+				{Field: "page", Code: api.FieldUnknown, Message: "unknown query parameter: page"},
+				{Field: "offset", Code: api.FieldOutOfRange, Message: "offset must not be negative"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+			handler.SetStrictQuery(true)
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Nil(t, mock.capturedCtx)
+
+			var problem api.Problem
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+			assert.Equal(t, api.CodeInvalidQueryParameter, problem.Code)
+			assert.Equal(t, tc.errors, problem.Errors)
+		})
+	}
+}
+
+func TestCatalogHandleGetStrictQueryAcceptsValidParameters(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	handler.SetStrictQuery(true)
+	req := httptest.NewRequest(http.MethodGet, "/catalog?offset=20&limit=100&category=SHOES&sort=-price&money=string", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, 20, mock.capturedQuery.Offset)
+	assert.Equal(t, 100, mock.capturedQuery.Limit)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, 20, payload.Offset)
+	assert.Equal(t, 100, payload.Limit)
+}
+
+func TestCatalogHandleGetStrictQueryPreference(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		serverStrict bool
+		prefer       string
+		status       int
+		applied      string
+	}{
+		"lenient server":                 {status: http.StatusOK},
+		"strict server":                  {serverStrict: true, status: http.StatusBadRequest},
+		"strict request":                 {prefer: "handling=strict", status: http.StatusBadRequest, applied: "handling=strict"},
+		"lenient request":                {serverStrict: true, prefer: "handling=lenient", status: http.StatusOK, applied: "handling=lenient"},
+		"among other preferences":        {prefer: "return=minimal, Handling=Strict", status: http.StatusBadRequest, applied: "handling=strict"},
+		"unrelated preference":           {serverStrict: true, prefer: "return=minimal", status: http.StatusBadRequest},
+		"preference with parameters":     {prefer: "handling=strict; foo=bar", status: http.StatusBadRequest, applied: "handling=strict"},
+		"unknown handling preference":    {prefer: "handling=loose", status: http.StatusOK},
+		"strict server unknown handling": {serverStrict: true, prefer: "handling=loose", status: http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+			handler.SetStrictQuery(tc.serverStrict)
+			req := httptest.NewRequest(http.MethodGet, "/catalog?limit=500", nil)
+			if tc.prefer != "" {
+				req.Header.Set("Prefer", tc.prefer)
+			}
+			res := httptest.NewRecorder()
+
+			handler.HandleGet(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, tc.applied, res.Header().Get("Preference-Applied"))
+			assert.Equal(t, "Prefer", res.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestCatalogHandleGetEchoesLenientPagination(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog?offset=-5&limit=500&page=2", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var payload Response
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, 0, payload.Offset)
+	assert.Equal(t, 100, payload.Limit)
+}
@@ -0,0 +1,266 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxVariantSKULength  = 32
+	maxVariantNameLength = 256
+)
+
+// VariantReaderWriter defines variant operations consumed by the variants handler.
+type VariantReaderWriter interface {
+	ListVariants(ctx context.Context, productCode string) ([]models.Variant, error)
+	CreateVariant(ctx context.Context, productCode string, input models.VariantInput) (*models.Variant, error)
+	UpdateVariant(ctx context.Context, productCode, sku string, update models.VariantUpdate) (*models.Variant, error)
+	DeleteVariant(ctx context.Context, productCode, sku string) error
+}
+
+// VariantsHandler exposes HTTP handlers for product variant management.
+type VariantsHandler struct {
+	repo VariantReaderWriter
+}
+
+// NewVariantsHandler creates a new VariantsHandler.
+func NewVariantsHandler(repo VariantReaderWriter) *VariantsHandler {
+	return &VariantsHandler{repo: repo}
+}
+
+// VariantResponse represents a variant as stored, including its optional price override.
+type VariantResponse struct {
+	SKU           string     `json:"sku"`
+	Name          string     `json:"name"`
+	PriceOverride *api.Money `json:"price_override"`
+}
+
+// VariantListResponse contains the variants of a product.
+type VariantListResponse struct {
+	Variants []VariantResponse `json:"variants"`
+}
+
+// CreateVariantRequest represents variant creation payload.
+type CreateVariantRequest struct {
+	SKU   string           `json:"sku"`
+	Name  string           `json:"name"`
+	Price *decimal.Decimal `json:"price_override"`
+}
+
+// UpdateVariantRequest represents variant update payload.
+// A null price_override clears the override so the variant inherits the product price.
+type UpdateVariantRequest struct {
+	Name  *string         `json:"name"`
+	Price nullableDecimal `json:"price_override"`
+}
+
+// nullableDecimal distinguishes an absent JSON field from an explicit null.
+type nullableDecimal struct {
+	Set   bool
+	Value *decimal.Decimal
+}
+
+// UnmarshalJSON records that the field was present and decodes its value.
+func (n *nullableDecimal) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var value decimal.Decimal
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+
+	return nil
+}
+
+// HandleList returns all variants of a product.
+func (h *VariantsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code")
+		return
+	}
+
+	format, ok := api.MoneyFormatFromRequest(w, r)
+	if !ok {
+		api.InvalidQueryParameterResponse(w, r, "money")
+		return
+	}
+
+	variants, err := h.repo.ListVariants(r.Context(), code)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch variants")
+		return
+	}
+
+	response := make([]VariantResponse, len(variants))
+	for i, variant := range variants {
+		response[i] = toVariantResponse(variant, format)
+	}
+
+	api.OKResponse(w, VariantListResponse{Variants: response})
+}
+
+// HandlePost validates and creates a new variant for a product.
+func (h *VariantsHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code")
+		return
+	}
+
+	format, ok := api.MoneyFormatFromRequest(w, r)
+	if !ok {
+		api.InvalidQueryParameterResponse(w, r, "money")
+		return
+	}
+
+	var req CreateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	req.SKU = strings.TrimSpace(req.SKU)
+	req.Name = strings.TrimSpace(req.Name)
+	var missing api.FieldErrors
+	missing.Require("sku", req.SKU != "")
+	missing.Require("name", req.Name != "")
+	if len(missing) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, missing...)
+		return
+	}
+
+	if utf8.RuneCountInString(req.SKU) > maxVariantSKULength {
+		api.InvalidFieldResponse(w, r, "sku", api.FieldTooLong, "sku is too long")
+		return
+	}
+
+	if utf8.RuneCountInString(req.Name) > maxVariantNameLength {
+		api.InvalidFieldResponse(w, r, "name", api.FieldTooLong, "name is too long")
+		return
+	}
+
+	if req.Price != nil && req.Price.IsNegative() {
+		api.InvalidFieldResponse(w, r, "price_override", api.FieldInvalid, "price_override must not be negative")
+		return
+	}
+
+	variant, err := h.repo.CreateVariant(r.Context(), code, models.VariantInput{SKU: req.SKU, Name: req.Name, Price: req.Price})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to create variant")
+		return
+	}
+
+	api.CreatedResponse(w, toVariantResponse(*variant, format))
+}
+
+// HandlePut replaces the name and price override of a variant.
+// An omitted price_override clears the override.
+func (h *VariantsHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	h.handleUpdate(w, r, true)
+}
+
+// HandlePatch partially updates a variant.
+func (h *VariantsHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	h.handleUpdate(w, r, false)
+}
+
+func (h *VariantsHandler) handleUpdate(w http.ResponseWriter, r *http.Request, replace bool) {
+	code, sku := r.PathValue("code"), r.PathValue("sku")
+	if code == "" || sku == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code or sku")
+		return
+	}
+
+	format, ok := api.MoneyFormatFromRequest(w, r)
+	if !ok {
+		api.InvalidQueryParameterResponse(w, r, "money")
+		return
+	}
+
+	var req UpdateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	if req.Name != nil {
+		trimmed := strings.TrimSpace(*req.Name)
+		req.Name = &trimmed
+	}
+
+	if replace {
+		if req.Name == nil {
+			api.ValidationErrorResponse(w, r, api.CodeValidationFailed, api.FieldError{Field: "name", Code: api.FieldRequired, Message: "name is required"})
+			return
+		}
+		req.Price.Set = true
+	}
+
+	if req.Name != nil && *req.Name == "" {
+		api.InvalidFieldResponse(w, r, "name", api.FieldRequired, "name must not be empty")
+		return
+	}
+
+	if req.Name != nil && utf8.RuneCountInString(*req.Name) > maxVariantNameLength {
+		api.InvalidFieldResponse(w, r, "name", api.FieldTooLong, "name is too long")
+		return
+	}
+
+	if req.Price.Value != nil && req.Price.Value.IsNegative() {
+		api.InvalidFieldResponse(w, r, "price_override", api.FieldInvalid, "price_override must not be negative")
+		return
+	}
+
+	variant, err := h.repo.UpdateVariant(r.Context(), code, sku, models.VariantUpdate{
+		Name:     req.Name,
+		SetPrice: req.Price.Set,
+		Price:    req.Price.Value,
+	})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to update variant")
+		return
+	}
+
+	api.OKResponse(w, toVariantResponse(*variant, format))
+}
+
+// HandleDelete removes a variant from a product.
+func (h *VariantsHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code, sku := r.PathValue("code"), r.PathValue("sku")
+	if code == "" || sku == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code or sku")
+		return
+	}
+
+	if err := h.repo.DeleteVariant(r.Context(), code, sku); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to delete variant")
+		return
+	}
+
+	api.NoContentResponse(w)
+}
+
+// toVariantResponse maps a variant to its response with the price override in the given money format.
+// Overrides are in the currency of their product, which the response leaves out.
+func toVariantResponse(variant models.Variant, format api.MoneyFormat) VariantResponse {
+	response := VariantResponse{SKU: variant.SKU, Name: variant.Name}
+	if variant.Price != nil {
+		price := api.NewMoney(*variant.Price, "", format)
+		response.PriceOverride = &price
+	}
+
+	return response
+}
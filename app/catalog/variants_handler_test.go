@@ -0,0 +1,278 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type variantsRepoMock struct {
+	variants       []models.Variant
+	err            error
+	capturedCode   string
+	capturedSKU    string
+	capturedInput  models.VariantInput
+	capturedUpdate models.VariantUpdate
+}
+
+func (m *variantsRepoMock) ListVariants(_ context.Context, productCode string) ([]models.Variant, error) {
+	m.capturedCode = productCode
+	return m.variants, m.err
+}
+
+func (m *variantsRepoMock) CreateVariant(_ context.Context, productCode string, input models.VariantInput) (*models.Variant, error) {
+	m.capturedCode = productCode
+	m.capturedInput = input
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &models.Variant{SKU: input.SKU, Name: input.Name, Price: input.Price}, nil
+}
+
+func (m *variantsRepoMock) UpdateVariant(_ context.Context, productCode, sku string, update models.VariantUpdate) (*models.Variant, error) {
+	m.capturedCode = productCode
+	m.capturedSKU = sku
+	m.capturedUpdate = update
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	variant := models.Variant{SKU: sku, Name: "Variant A", Price: update.Price}
+	if update.Name != nil {
+		variant.Name = *update.Name
+	}
+
+	return &variant, nil
+}
+
+func (m *variantsRepoMock) DeleteVariant(_ context.Context, productCode, sku string) error {
+	m.capturedCode = productCode
+	m.capturedSKU = sku
+	return m.err
+}
+
+func newVariantRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/catalog/PROD001/variants/SKU001A", bytes.NewBufferString(body))
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("sku", "SKU001A")
+	return req
+}
+
+func TestVariantsHandleList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns price overrides", func(t *testing.T) {
+		price := decimal.RequireFromString("11.99")
+		mock := &variantsRepoMock{variants: []models.Variant{
+			{SKU: "SKU001A", Name: "Variant A", Price: &price},
+			{SKU: "SKU001B", Name: "Variant B"},
+		}}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandleList(res, newVariantRequest(http.MethodGet, ""))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "PROD001", mock.capturedCode)
+
+		var payload VariantListResponse
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		require.Len(t, payload.Variants, 2)
+		require.NotNil(t, payload.Variants[0].PriceOverride)
+		assert.Equal(t, "11.99", payload.Variants[0].PriceOverride.Amount.String())
+		assert.Equal(t, api.MoneyFormatFloat, payload.Variants[0].PriceOverride.Format)
+		assert.Nil(t, payload.Variants[1].PriceOverride)
+	})
+
+	t.Run("exact price overrides", func(t *testing.T) {
+		price := decimal.RequireFromString("11.99")
+		handler := NewVariantsHandler(&variantsRepoMock{variants: []models.Variant{{SKU: "SKU001A", Name: "Variant A", Price: &price}}})
+		req := newVariantRequest(http.MethodGet, "")
+		req.Header.Set("Accept", api.MediaTypeV2)
+		res := httptest.NewRecorder()
+
+		handler.HandleList(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, api.MediaTypeV2, res.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", res.Header().Get("Vary"))
+		assert.Contains(t, res.Body.String(), `"price_override":{"amount":"11.99"}`)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		handler := NewVariantsHandler(&variantsRepoMock{err: models.ErrProductNotFound})
+		res := httptest.NewRecorder()
+
+		handler.HandleList(res, newVariantRequest(http.MethodGet, ""))
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestVariantsHandlePost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates variant", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newVariantRequest(http.MethodPost, `{"sku":"SKU001D","name":"Variant D","price_override":"12.50"}`))
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, "SKU001D", mock.capturedInput.SKU)
+		require.NotNil(t, mock.capturedInput.Price)
+		assert.True(t, decimal.RequireFromString("12.50").Equal(*mock.capturedInput.Price))
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		bodies := []string{
+			`{"sku":`,
+			`{"sku":"","name":"Variant D"}`,
+			`{"sku":"SKU001D","name":"Variant D","price_override":-1}`,
+			`{"sku":"SKU0000000000000000000000000000001D","name":"Variant D"}`,
+		}
+
+		for _, body := range bodies {
+			handler := NewVariantsHandler(&variantsRepoMock{})
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, newVariantRequest(http.MethodPost, body))
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		}
+	})
+
+	t.Run("multibyte sku and name", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		sku := strings.Repeat("Ä", maxVariantSKULength)
+		name := strings.Repeat("é", maxVariantNameLength)
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newVariantRequest(http.MethodPost, `{"sku":"`+sku+`","name":"`+name+`"}`))
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, sku, mock.capturedInput.SKU)
+		assert.Equal(t, name, mock.capturedInput.Name)
+	})
+
+	t.Run("duplicate sku", func(t *testing.T) {
+		handler := NewVariantsHandler(&variantsRepoMock{err: models.ErrVariantSKUAlreadyExists})
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newVariantRequest(http.MethodPost, `{"sku":"SKU001A","name":"Variant A"}`))
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+	})
+}
+
+func TestVariantsHandlePatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("absent price keeps override", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, newVariantRequest(http.MethodPatch, `{"name":"Renamed"}`))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "SKU001A", mock.capturedSKU)
+		assert.False(t, mock.capturedUpdate.SetPrice)
+	})
+
+	t.Run("null price clears override", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, newVariantRequest(http.MethodPatch, `{"price_override":null}`))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.True(t, mock.capturedUpdate.SetPrice)
+		assert.Nil(t, mock.capturedUpdate.Price)
+	})
+
+	t.Run("sets override", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, newVariantRequest(http.MethodPatch, `{"price_override":9.5}`))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.True(t, mock.capturedUpdate.SetPrice)
+		require.NotNil(t, mock.capturedUpdate.Price)
+		assert.True(t, decimal.RequireFromString("9.5").Equal(*mock.capturedUpdate.Price))
+	})
+
+	t.Run("unknown variant", func(t *testing.T) {
+		handler := NewVariantsHandler(&variantsRepoMock{err: models.ErrVariantNotFound})
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, newVariantRequest(http.MethodPatch, `{"name":"Renamed"}`))
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestVariantsHandlePut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("omitted price clears override", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, newVariantRequest(http.MethodPut, `{"name":"Variant A"}`))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.True(t, mock.capturedUpdate.SetPrice)
+		assert.Nil(t, mock.capturedUpdate.Price)
+	})
+
+	t.Run("requires name", func(t *testing.T) {
+		handler := NewVariantsHandler(&variantsRepoMock{})
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, newVariantRequest(http.MethodPut, `{"price_override":1}`))
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
+
+func TestVariantsHandleDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes variant", func(t *testing.T) {
+		mock := &variantsRepoMock{}
+		handler := NewVariantsHandler(mock)
+		res := httptest.NewRecorder()
+
+		handler.HandleDelete(res, newVariantRequest(http.MethodDelete, ""))
+
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, "SKU001A", mock.capturedSKU)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		handler := NewVariantsHandler(&variantsRepoMock{err: assert.AnError})
+		res := httptest.NewRecorder()
+
+		handler.HandleDelete(res, newVariantRequest(http.MethodDelete, ""))
+
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+	})
+}
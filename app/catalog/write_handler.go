@@ -0,0 +1,298 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+const maxProductCodeLength = 32
+
+// maxPrice bounds product prices to fit the DECIMAL(10, 2) price column.
+var maxPrice = decimal.New(1, 8)
+
+// CreateProductRequest represents product creation payload.
+type CreateProductRequest struct {
+	Code        string           `json:"code"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       *decimal.Decimal `json:"price"`
+	Currency    string           `json:"currency"`
+	Category    string           `json:"category"`
+}
+
+// UpdateProductRequest represents product update payload.
+// PUT requires price and category, PATCH only applies the fields that are present.
+// Name, description and currency are only changed when present.
+type UpdateProductRequest struct {
+	Name        *string          `json:"name"`
+	Description *string          `json:"description"`
+	Price       *decimal.Decimal `json:"price"`
+	Currency    *string          `json:"currency"`
+	Category    *string          `json:"category"`
+}
+
+// HandlePost validates and creates a new product.
+func (h *CatalogHandler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	prices, invalid := h.parsePriceOptions(w, r)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	var req CreateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	req.Code = strings.TrimSpace(req.Code)
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	req.Category = strings.TrimSpace(req.Category)
+	var missing api.FieldErrors
+	missing.Require("code", req.Code != "")
+	missing.Require("price", req.Price != nil)
+	missing.Require("category", req.Category != "")
+	if len(missing) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, missing...)
+		return
+	}
+
+	if utf8.RuneCountInString(req.Code) > maxProductCodeLength {
+		api.InvalidFieldResponse(w, r, "code", api.FieldTooLong, "code must be at most 32 characters")
+		return
+	}
+
+	if models.IsReservedProductCode(req.Code) {
+		api.InvalidFieldResponse(w, r, "code", api.FieldInvalid, "code is reserved")
+		return
+	}
+
+	if code, message := checkPrice(*req.Price); code != "" {
+		api.InvalidFieldResponse(w, r, "price", code, message)
+		return
+	}
+
+	if req.Currency != "" {
+		currency, ok := models.NormalizeCurrency(req.Currency)
+		if !ok {
+			api.InvalidFieldResponse(w, r, "currency", api.FieldInvalid, "currency must be a 3-letter code")
+			return
+		}
+		req.Currency = currency
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	if err := h.checkWritePrices(r.Context(), prices, currency); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
+		return
+	}
+
+	product, err := h.repo.CreateProduct(r.Context(), models.ProductInput{
+		Code:         req.Code,
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        *req.Price,
+		Currency:     req.Currency,
+		CategoryCode: req.Category,
+	})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to create product")
+		return
+	}
+
+	api.CreatedResponse(w, h.writtenProductDetails(r.Context(), product, prices))
+}
+
+// HandlePut replaces the writable fields of an existing product.
+func (h *CatalogHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	h.handleUpdate(w, r, true)
+}
+
+// HandlePatch partially updates an existing product.
+func (h *CatalogHandler) HandlePatch(w http.ResponseWriter, r *http.Request) {
+	h.handleUpdate(w, r, false)
+}
+
+func (h *CatalogHandler) handleUpdate(w http.ResponseWriter, r *http.Request, replace bool) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code")
+		return
+	}
+
+	prices, invalid := h.parsePriceOptions(w, r)
+	if invalid != "" {
+		api.InvalidQueryParameterResponse(w, r, invalid)
+		return
+	}
+
+	var req UpdateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	req.Name = trimOptional(req.Name)
+	req.Description = trimOptional(req.Description)
+	req.Category = trimOptional(req.Category)
+
+	if replace {
+		var missing api.FieldErrors
+		missing.Require("price", req.Price != nil)
+		missing.Require("category", req.Category != nil)
+		if len(missing) > 0 {
+			api.ValidationErrorResponse(w, r, api.CodeValidationFailed, missing...)
+			return
+		}
+	}
+
+	if req.Category != nil && *req.Category == "" {
+		api.InvalidFieldResponse(w, r, "category", api.FieldRequired, "category must not be empty")
+		return
+	}
+
+	if req.Price != nil {
+		if code, message := checkPrice(*req.Price); code != "" {
+			api.InvalidFieldResponse(w, r, "price", code, message)
+			return
+		}
+	}
+
+	if req.Currency != nil {
+		currency, ok := models.NormalizeCurrency(*req.Currency)
+		if !ok {
+			api.InvalidFieldResponse(w, r, "currency", api.FieldInvalid, "currency must be a 3-letter code")
+			return
+		}
+		req.Currency = &currency
+	}
+
+	var currency string
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+
+	if err := h.checkWritePrices(r.Context(), prices, currency); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to resolve prices")
+		return
+	}
+
+	product, err := h.repo.UpdateProduct(r.Context(), code, models.ProductUpdate{
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        req.Price,
+		Currency:     req.Currency,
+		CategoryCode: req.Category,
+	})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to update product")
+		return
+	}
+
+	api.OKResponse(w, h.writtenProductDetails(r.Context(), product, prices))
+}
+
+// HandleDelete removes a product and its variants.
+func (h *CatalogHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing product code")
+		return
+	}
+
+	if err := h.repo.DeleteProduct(r.Context(), code); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to delete product")
+		return
+	}
+
+	api.NoContentResponse(w)
+}
+
+// checkWritePrices verifies before a product write that its response can show the requested prices,
+// so that the write does not commit and then fail: the market must exist, and prices in currency and in
+// the market's currency must convert into the requested currency, or into the market currency without
+// one. An empty currency is not checked.
+func (h *CatalogHandler) checkWritePrices(ctx context.Context, prices priceOptions, currency string) error {
+	var currencies []string
+	if currency != "" {
+		currencies = append(currencies, currency)
+	}
+
+	converter := prices.converter
+	if prices.market != "" {
+		book, err := h.priceBooks.GetPriceBook(ctx, prices.market, prices.at, nil)
+		if err != nil {
+			return err
+		}
+
+		if book != nil && book.Market != nil {
+			currencies = append(currencies, book.Market.Currency)
+		}
+		converter = h.marketConverter(prices, book)
+	}
+
+	for _, from := range currencies {
+		if err := converter.check(ctx, from); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writtenProductDetails builds the details of a product that has just been written. The write is
+// committed, so a failure to resolve its prices, such as a timeout, falls back to the stored prices.
+func (h *CatalogHandler) writtenProductDetails(ctx context.Context, product *models.Product, prices priceOptions) ProductDetailsResponse {
+	details, err := h.productDetails(ctx, product, prices)
+	if err == nil {
+		return details
+	}
+
+	slog.WarnContext(ctx, "resolving prices of written product failed, returning stored prices",
+		slog.String("code", product.Code),
+		slog.String("error", err.Error()),
+		slog.String("request_id", requestid.FromContext(ctx)),
+	)
+
+	var book *models.PriceBook
+	return h.detailsService.BuildProductDetails(product, book.Resolve(*product), prices.format)
+}
+
+// trimOptional trims surrounding whitespace from an optional string field.
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
+
+// checkPrice returns the field code and message rejecting a price that does not fit the price column,
+// or an empty code for a valid price.
+func checkPrice(price decimal.Decimal) (string, string) {
+	switch {
+	case price.IsNegative():
+		return api.FieldInvalid, "price must not be negative"
+	case price.GreaterThanOrEqual(maxPrice):
+		return api.FieldOutOfRange, "price must be less than 100000000"
+	case !price.Equal(price.Round(2)):
+		return api.FieldInvalid, "price must have at most 2 decimal places"
+	default:
+		return "", ""
+	}
+}
@@ -0,0 +1,384 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogHandlePostSuccess(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+
+	body := []byte(`{"code":" PROD009 ","price":"19.90","category":"SHOES"}`)
+	req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBuffer(body))
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "PROD009", mock.capturedInput.Code)
+	assert.Equal(t, "SHOES", mock.capturedInput.CategoryCode)
+	assert.True(t, decimal.RequireFromString("19.90").Equal(mock.capturedInput.Price))
+
+	var payload ProductDetailsResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "PROD009", payload.Code)
+	assert.Equal(t, "19.9", payload.Price.Amount.String())
+	assert.Empty(t, payload.Variants)
+}
+
+func TestCatalogHandlePostChecksPricesBeforeWriting(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target     string
+		priceBooks *priceBooksMock
+		rates      map[string]decimal.Decimal
+	}{
+		"unknown market":         {target: "/catalog?market=ZZ", priceBooks: &priceBooksMock{err: models.ErrMarketNotFound}},
+		"unconvertible currency": {target: "/catalog?currency=GBP", priceBooks: &priceBooksMock{}},
+		"unconvertible market currency": {
+			target:     "/catalog?market=US&currency=GBP",
+			priceBooks: &priceBooksMock{book: models.NewPriceBook(&models.Market{Code: "US", Currency: "USD"}, nil, nil)},
+			rates:      map[string]decimal.Decimal{"EURGBP": decimal.RequireFromString("0.85")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &productsReaderMock{}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{rates: tc.rates}, tc.priceBooks)
+			body := []byte(`{"code":"PROD009","price":"19.90","category":"SHOES"}`)
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBuffer(body))
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Empty(t, mock.capturedInput.Code)
+		})
+	}
+}
+
+func TestCatalogHandlePatchFallsBackToStoredPrices(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{productByCode: &models.Product{Code: "PROD001", Price: decimal.RequireFromString("10.99"), Currency: "USD"}}
+	rates := &exchangeRatesMock{rates: map[string]decimal.Decimal{"EURGBP": decimal.RequireFromString("0.85")}}
+	handler := NewCatalogHandler(mock, rates, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001?currency=GBP", bytes.NewBufferString(`{"name":"Linen Shirt"}`))
+	req.SetPathValue("code", "PROD001")
+	res := httptest.NewRecorder()
+
+	handler.HandlePatch(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var payload ProductDetailsResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "10.99", payload.Price.Amount.String())
+	assert.Equal(t, "USD", payload.Currency)
+}
+
+func TestCatalogHandlePostNameAndDescription(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+
+	body := []byte(`{"code":"PROD009","name":" Linen Shirt ","description":"Light linen.","price":"19.90","category":"SHOES"}`)
+	req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBuffer(body))
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "Linen Shirt", mock.capturedInput.Name)
+	assert.Equal(t, "Light linen.", mock.capturedInput.Description)
+}
+
+func TestCatalogHandlePostValidation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"malformed json":    `{"code":`,
+		"missing code":      `{"price":1,"category":"SHOES"}`,
+		"missing price":     `{"code":"PROD009","category":"SHOES"}`,
+		"missing category":  `{"code":"PROD009","price":1}`,
+		"negative price":    `{"code":"PROD009","price":-1,"category":"SHOES"}`,
+		"price too large":   `{"code":"PROD009","price":100000000,"category":"SHOES"}`,
+		"price too precise": `{"code":"PROD009","price":"9.999","category":"SHOES"}`,
+		"code too long":     `{"code":"PROD0000000000000000000000000000009","price":1,"category":"SHOES"}`,
+		"invalid currency":  `{"code":"PROD009","price":1,"currency":"EURO","category":"SHOES"}`,
+		"reserved code":     `{"code":"search","price":1,"category":"SHOES"}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBufferString(body))
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+		})
+	}
+}
+
+func TestCatalogHandlePostFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+	req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBufferString(`{"code":"PROD009"}`))
+	req.Header.Set(requestid.Header, "req-1")
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+
+	var problem api.Problem
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+	assert.Equal(t, api.CodeValidationFailed, problem.Code)
+	assert.Equal(t, "req-1", problem.RequestID)
+	assert.Equal(t, []api.FieldError{
+		{Field: "price", Code: api.FieldRequired, Message: "price is required"},
+		{Field: "category", Code: api.FieldRequired, Message: "category is required"},
+	}, problem.Errors)
+}
+
+func TestCatalogHandlePostMultibyteCode(t *testing.T) {
+	t.Parallel()
+
+	mock := &productsReaderMock{}
+	handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+	code := strings.Repeat("Ä", maxProductCodeLength)
+	req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBufferString(`{"code":"`+code+`","price":1,"category":"SHOES"}`))
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, code, mock.capturedInput.Code)
+}
+
+func TestCatalogHandlePostRepositoryErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err    error
+		status int
+	}{
+		"duplicate code":   {err: models.ErrProductCodeAlreadyExists, status: http.StatusConflict},
+		"unknown category": {err: models.ErrProductCategoryNotFound, status: http.StatusBadRequest},
+		"database failure": {err: assert.AnError, status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewCatalogHandler(&productsReaderMock{writeErr: tc.err}, &exchangeRatesMock{}, &priceBooksMock{})
+			body := []byte(`{"code":"PROD001","price":1,"category":"SHOES"}`)
+			req := httptest.NewRequest(http.MethodPost, "/catalog", bytes.NewBuffer(body))
+			res := httptest.NewRecorder()
+
+			handler.HandlePost(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestCatalogHandlePut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replaces price and category", func(t *testing.T) {
+		mock := &productsReaderMock{
+			productByCode: &models.Product{
+				Code:     "PROD001",
+				Price:    decimal.RequireFromString("12"),
+				Category: models.Category{Code: "SHOES", Name: "Shoes"},
+			},
+		}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"price":12,"category":"SHOES"}`)
+		req := httptest.NewRequest(http.MethodPut, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "PROD001", mock.capturedCode)
+		require.NotNil(t, mock.capturedUpdate.Price)
+		require.NotNil(t, mock.capturedUpdate.CategoryCode)
+		assert.Equal(t, "SHOES", *mock.capturedUpdate.CategoryCode)
+	})
+
+	t.Run("requires every field", func(t *testing.T) {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"price":12}`)
+		req := httptest.NewRequest(http.MethodPut, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePut(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
+
+func TestCatalogHandlePatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates name and description", func(t *testing.T) {
+		mock := &productsReaderMock{productByCode: &models.Product{Code: "PROD001", Name: "Shirt"}}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"name":" Shirt ","description":""}`)
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		require.NotNil(t, mock.capturedUpdate.Name)
+		require.NotNil(t, mock.capturedUpdate.Description)
+		assert.Equal(t, "Shirt", *mock.capturedUpdate.Name)
+		assert.Empty(t, *mock.capturedUpdate.Description)
+		assert.Nil(t, mock.capturedUpdate.Price)
+	})
+
+	t.Run("applies only present fields", func(t *testing.T) {
+		mock := &productsReaderMock{
+			productByCode: &models.Product{Code: "PROD001", Price: decimal.RequireFromString("9.99")},
+		}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"price":"9.99"}`)
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		require.NotNil(t, mock.capturedUpdate.Price)
+		assert.Nil(t, mock.capturedUpdate.CategoryCode)
+	})
+
+	t.Run("rejects empty category", func(t *testing.T) {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"category":" "}`)
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("rejects prices not fitting the price column", func(t *testing.T) {
+		cases := map[string]api.FieldError{
+			`{"price":"100000000.00"}`: {Field: "price", Code: api.FieldOutOfRange, Message: "price must be less than 100000000"},
+			`{"price":"0.001"}`:        {Field: "price", Code: api.FieldInvalid, Message: "price must have at most 2 decimal places"},
+			`{"price":"-0.01"}`:        {Field: "price", Code: api.FieldInvalid, Message: "price must not be negative"},
+		}
+
+		for body, want := range cases {
+			mock := &productsReaderMock{}
+			handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+			req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBufferString(body))
+			req.SetPathValue("code", "PROD001")
+			res := httptest.NewRecorder()
+
+			handler.HandlePatch(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code, body)
+			var problem api.Problem
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+			assert.Equal(t, []api.FieldError{want}, problem.Errors, body)
+			assert.Nil(t, mock.capturedUpdate.Price, body)
+		}
+	})
+
+	t.Run("accepts the largest price", func(t *testing.T) {
+		mock := &productsReaderMock{productByCode: &models.Product{Code: "PROD001"}}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"price":"99999999.990"}`)
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBuffer(body))
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		require.NotNil(t, mock.capturedUpdate.Price)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		handler := NewCatalogHandler(&productsReaderMock{writeErr: models.ErrProductNotFound}, &exchangeRatesMock{}, &priceBooksMock{})
+		body := []byte(`{"price":1}`)
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/MISSING", bytes.NewBuffer(body))
+		req.SetPathValue("code", "MISSING")
+		res := httptest.NewRecorder()
+
+		handler.HandlePatch(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestCatalogHandleDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes product", func(t *testing.T) {
+		mock := &productsReaderMock{}
+		handler := NewCatalogHandler(mock, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodDelete, "/catalog/PROD001", nil)
+		req.SetPathValue("code", "PROD001")
+		res := httptest.NewRecorder()
+
+		handler.HandleDelete(res, req)
+
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, "PROD001", mock.capturedCode)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		handler := NewCatalogHandler(&productsReaderMock{writeErr: models.ErrProductNotFound}, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodDelete, "/catalog/MISSING", nil)
+		req.SetPathValue("code", "MISSING")
+		res := httptest.NewRecorder()
+
+		handler.HandleDelete(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		handler := NewCatalogHandler(&productsReaderMock{}, &exchangeRatesMock{}, &priceBooksMock{})
+		req := httptest.NewRequest(http.MethodDelete, "/catalog/", nil)
+		res := httptest.NewRecorder()
+
+		handler.HandleDelete(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
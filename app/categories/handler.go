@@ -1,8 +1,8 @@
 package categories
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strings"
 
@@ -12,8 +12,11 @@ import (
 
 // CategoryReaderWriter defines category operations consumed by the handler.
 type CategoryReaderWriter interface {
-	GetAllCategories() ([]models.Category, error)
-	CreateCategory(category models.Category) (*models.Category, error)
+	GetAllCategories(ctx context.Context) ([]models.Category, error)
+	CreateCategory(ctx context.Context, input models.CategoryInput) (*models.Category, error)
+	GetCategoryByCode(ctx context.Context, code string) (*models.Category, error)
+	UpdateCategory(ctx context.Context, code string, input models.CategoryInput) (*models.Category, error)
+	DeleteCategory(ctx context.Context, code, reassignTo string) error
 }
 
 // Handler exposes HTTP handlers for category endpoints.
@@ -27,9 +30,11 @@ func NewHandler(repo CategoryReaderWriter) *Handler {
 }
 
 // CategoryResponse represents category data returned by API responses.
+// Parent holds the parent category code and is omitted for root categories.
 type CategoryResponse struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
 }
 
 // ListResponse contains category list payload.
@@ -37,53 +42,197 @@ type ListResponse struct {
 	Categories []CategoryResponse `json:"categories"`
 }
 
+// TreeNode represents a category and its subcategories.
+type TreeNode struct {
+	Code     string     `json:"code"`
+	Name     string     `json:"name"`
+	Children []TreeNode `json:"children"`
+}
+
+// TreeResponse contains the category tree payload.
+type TreeResponse struct {
+	Categories []TreeNode `json:"categories"`
+}
+
 // HandleGet returns all categories.
 func (h *Handler) HandleGet(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.repo.GetAllCategories()
+	categories, err := h.repo.GetAllCategories(r.Context())
 	if err != nil {
-		api.ErrorResponse(w, http.StatusInternalServerError, "failed to fetch categories")
+		api.DomainErrorResponse(w, r, err, "failed to fetch categories")
 		return
 	}
 
 	response := make([]CategoryResponse, len(categories))
 	for i, category := range categories {
-		response[i] = CategoryResponse{Code: category.Code, Name: category.Name}
+		response[i] = newCategoryResponse(category)
 	}
 
 	api.OKResponse(w, ListResponse{Categories: response})
 }
 
+// HandleGetTree returns all categories nested below their parents.
+func (h *Handler) HandleGetTree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.repo.GetAllCategories(r.Context())
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch categories")
+		return
+	}
+
+	api.OKResponse(w, TreeResponse{Categories: buildTree(categories)})
+}
+
+// HandleGetByCode returns a single category by code.
+func (h *Handler) HandleGetByCode(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing category code")
+		return
+	}
+
+	category, err := h.repo.GetCategoryByCode(r.Context(), code)
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch category")
+		return
+	}
+
+	api.OKResponse(w, newCategoryResponse(*category))
+}
+
 // CreateCategoryRequest represents category creation payload.
+// Parent is the optional code of the parent category.
 type CreateCategoryRequest struct {
-	Code string `json:"code"`
-	Name string `json:"name"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
 }
 
 // HandlePost validates and creates a new category.
 func (h *Handler) HandlePost(w http.ResponseWriter, r *http.Request) {
 	var req CreateCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		api.ErrorResponse(w, http.StatusBadRequest, "invalid request body")
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
 		return
 	}
 
-	req.Code = strings.TrimSpace(req.Code)
-	req.Name = strings.TrimSpace(req.Name)
-	if req.Code == "" || req.Name == "" {
-		api.ErrorResponse(w, http.StatusBadRequest, "code and name are required")
+	if fields := normalizeCategoryRequest(&req); len(fields) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, fields...)
 		return
 	}
 
-	created, err := h.repo.CreateCategory(models.Category{Code: req.Code, Name: req.Name})
+	created, err := h.repo.CreateCategory(r.Context(), models.CategoryInput{Code: req.Code, Name: req.Name, ParentCode: req.Parent})
 	if err != nil {
-		if errors.Is(err, models.ErrCategoryCodeAlreadyExists) {
-			api.ErrorResponse(w, http.StatusConflict, "category code already exists")
-			return
-		}
+		api.DomainErrorResponse(w, r, err, "failed to create category")
+		return
+	}
+
+	api.CreatedResponse(w, newCategoryResponse(*created))
+}
+
+// HandlePut replaces the code, name and parent of an existing category.
+func (h *Handler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing category code")
+		return
+	}
+
+	var req CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	if fields := normalizeCategoryRequest(&req); len(fields) > 0 {
+		api.ValidationErrorResponse(w, r, api.CodeValidationFailed, fields...)
+		return
+	}
 
-		api.ErrorResponse(w, http.StatusInternalServerError, "failed to create category")
+	if req.Parent == code {
+		api.InvalidFieldResponse(w, r, "parent", api.FieldInvalid, "category cannot be its own parent")
 		return
 	}
 
-	api.CreatedResponse(w, CategoryResponse{Code: created.Code, Name: created.Name})
+	updated, err := h.repo.UpdateCategory(r.Context(), code, models.CategoryInput{Code: req.Code, Name: req.Name, ParentCode: req.Parent})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to update category")
+		return
+	}
+
+	api.OKResponse(w, newCategoryResponse(*updated))
+}
+
+// HandleDelete removes a category.
+// The optional reassign_to query parameter moves its products to another category first.
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	if code == "" {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "missing category code")
+		return
+	}
+
+	reassignTo := strings.TrimSpace(r.URL.Query().Get("reassign_to"))
+	if reassignTo == code {
+		api.InvalidQueryParameterResponse(w, r, "reassign_to")
+		return
+	}
+
+	if err := h.repo.DeleteCategory(r.Context(), code, reassignTo); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to delete category")
+		return
+	}
+
+	api.NoContentResponse(w)
+}
+
+// normalizeCategoryRequest trims the fields of a category payload and returns the missing required fields.
+func normalizeCategoryRequest(req *CreateCategoryRequest) api.FieldErrors {
+	req.Code = strings.TrimSpace(req.Code)
+	req.Name = strings.TrimSpace(req.Name)
+	req.Parent = strings.TrimSpace(req.Parent)
+
+	var fields api.FieldErrors
+	fields.Require("code", req.Code != "")
+	fields.Require("name", req.Name != "")
+	return fields
+}
+
+// newCategoryResponse maps a category to its API representation.
+func newCategoryResponse(category models.Category) CategoryResponse {
+	response := CategoryResponse{Code: category.Code, Name: category.Name}
+	if category.Parent != nil {
+		response.Parent = category.Parent.Code
+	}
+
+	return response
+}
+
+// buildTree nests categories below their parents, keeping the input order among siblings.
+func buildTree(categories []models.Category) []TreeNode {
+	children := make(map[uint][]models.Category)
+	known := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil || !known[*category.ParentID] {
+			roots = append(roots, category)
+			continue
+		}
+
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	var build func(nodes []models.Category) []TreeNode
+	build = func(nodes []models.Category) []TreeNode {
+		tree := make([]TreeNode, len(nodes))
+		for i, node := range nodes {
+			tree[i] = TreeNode{Code: node.Code, Name: node.Name, Children: build(children[node.ID])}
+		}
+
+		return tree
+	}
+
+	return build(roots)
 }
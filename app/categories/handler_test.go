@@ -2,8 +2,10 @@ package categories
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -16,10 +18,15 @@ type categoriesRepoMock struct {
 	categories       []models.Category
 	getErr           error
 	createErr        error
-	capturedCategory *models.Category
+	capturedCategory *models.CategoryInput
+	getByCodeErr     error
+	updateErr        error
+	deleteErr        error
+	capturedCode     string
+	capturedReassign string
 }
 
-func (m *categoriesRepoMock) GetAllCategories() ([]models.Category, error) {
+func (m *categoriesRepoMock) GetAllCategories(_ context.Context) ([]models.Category, error) {
 	if m.getErr != nil {
 		return nil, m.getErr
 	}
@@ -27,13 +34,51 @@ func (m *categoriesRepoMock) GetAllCategories() ([]models.Category, error) {
 	return m.categories, nil
 }
 
-func (m *categoriesRepoMock) CreateCategory(category models.Category) (*models.Category, error) {
-	m.capturedCategory = &category
+func (m *categoriesRepoMock) CreateCategory(_ context.Context, input models.CategoryInput) (*models.Category, error) {
+	m.capturedCategory = &input
 	if m.createErr != nil {
 		return nil, m.createErr
 	}
 
-	return &category, nil
+	return newMockCategory(input), nil
+}
+
+func (m *categoriesRepoMock) GetCategoryByCode(_ context.Context, code string) (*models.Category, error) {
+	m.capturedCode = code
+	if m.getByCodeErr != nil {
+		return nil, m.getByCodeErr
+	}
+
+	return &models.Category{Code: code, Name: "Shoes"}, nil
+}
+
+func (m *categoriesRepoMock) UpdateCategory(_ context.Context, code string, input models.CategoryInput) (*models.Category, error) {
+	m.capturedCode = code
+	m.capturedCategory = &input
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+
+	return newMockCategory(input), nil
+}
+
+func (m *categoriesRepoMock) DeleteCategory(_ context.Context, code, reassignTo string) error {
+	m.capturedCode = code
+	m.capturedReassign = reassignTo
+	return m.deleteErr
+}
+
+func newMockCategory(input models.CategoryInput) *models.Category {
+	category := &models.Category{Code: input.Code, Name: input.Name}
+	if input.ParentCode != "" {
+		category.Parent = &models.Category{Code: input.ParentCode}
+	}
+
+	return category
+}
+
+func uintPtr(v uint) *uint {
+	return &v
 }
 
 func TestHandleGetCategoriesSuccess(t *testing.T) {
@@ -71,6 +116,19 @@ func TestHandleGetCategoriesError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, res.Code)
 }
 
+func TestHandleGetCategoryByCodeTimeout(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{getByCodeErr: fmt.Errorf("find category failed: %w", context.DeadlineExceeded)})
+	req := httptest.NewRequest(http.MethodGet, "/categories/SHOES", nil)
+	req.SetPathValue("code", "SHOES")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, res.Code)
+}
+
 func TestHandlePostCategorySuccess(t *testing.T) {
 	t.Parallel()
 
@@ -87,6 +145,80 @@ func TestHandlePostCategorySuccess(t *testing.T) {
 	assert.NotNil(t, mock.capturedCategory)
 	assert.Equal(t, "BAGS", mock.capturedCategory.Code)
 	assert.Equal(t, "Bags", mock.capturedCategory.Name)
+	assert.Empty(t, mock.capturedCategory.ParentCode)
+}
+
+func TestHandlePostCategoryWithParent(t *testing.T) {
+	t.Parallel()
+
+	mock := &categoriesRepoMock{}
+	handler := NewHandler(mock)
+
+	body := []byte(`{"code":"DRESSES","name":"Dresses","parent":" CLOTHING "}`)
+	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "CLOTHING", mock.capturedCategory.ParentCode)
+
+	var payload CategoryResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "CLOTHING", payload.Parent)
+}
+
+func TestHandlePostCategoryParentNotFound(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{createErr: models.ErrParentCategoryNotFound})
+
+	body := []byte(`{"code":"DRESSES","name":"Dresses","parent":"MISSING"}`)
+	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBuffer(body))
+	res := httptest.NewRecorder()
+
+	handler.HandlePost(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestHandleGetCategoryTree(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{
+		categories: []models.Category{
+			{ID: 1, Code: "CLOTHING", Name: "Clothing"},
+			{ID: 2, Code: "SHOES", Name: "Shoes"},
+			{ID: 3, Code: "DRESSES", Name: "Dresses", ParentID: uintPtr(1)},
+			{ID: 4, Code: "MAXI", Name: "Maxi", ParentID: uintPtr(3)},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/tree", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGetTree(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var payload TreeResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Len(t, payload.Categories, 2)
+	assert.Equal(t, "CLOTHING", payload.Categories[0].Code)
+	assert.Equal(t, "DRESSES", payload.Categories[0].Children[0].Code)
+	assert.Equal(t, "MAXI", payload.Categories[0].Children[0].Children[0].Code)
+	assert.Empty(t, payload.Categories[1].Children)
+}
+
+func TestHandleGetCategoryTreeError(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{getErr: errors.New("db error")})
+	req := httptest.NewRequest(http.MethodGet, "/categories/tree", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGetTree(res, req)
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
 }
 
 func TestHandlePostCategoryValidationError(t *testing.T) {
@@ -144,3 +276,147 @@ func TestHandlePostCategoryRepositoryError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.Code)
 }
+
+func TestHandleGetCategoryByCode(t *testing.T) {
+	t.Parallel()
+
+	mock := &categoriesRepoMock{}
+	handler := NewHandler(mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/SHOES", nil)
+	req.SetPathValue("code", "SHOES")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var payload CategoryResponse
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	assert.Equal(t, "SHOES", payload.Code)
+	assert.Equal(t, "SHOES", mock.capturedCode)
+}
+
+func TestHandleGetCategoryByCodeNotFound(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{getByCodeErr: models.ErrCategoryNotFound})
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/MISSING", nil)
+	req.SetPathValue("code", "MISSING")
+	res := httptest.NewRecorder()
+
+	handler.HandleGetByCode(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestHandlePutCategorySuccess(t *testing.T) {
+	t.Parallel()
+
+	mock := &categoriesRepoMock{}
+	handler := NewHandler(mock)
+
+	body := []byte(`{"code":" FOOTWEAR ","name":"Footwear"}`)
+	req := httptest.NewRequest(http.MethodPut, "/categories/SHOES", bytes.NewBuffer(body))
+	req.SetPathValue("code", "SHOES")
+	res := httptest.NewRecorder()
+
+	handler.HandlePut(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "SHOES", mock.capturedCode)
+	assert.Equal(t, "FOOTWEAR", mock.capturedCategory.Code)
+	assert.Equal(t, "Footwear", mock.capturedCategory.Name)
+}
+
+func TestHandlePutCategoryErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		body   string
+		err    error
+		status int
+	}{
+		"malformed json": {body: `{"code":`, status: http.StatusBadRequest},
+		"missing fields": {body: `{"code":"SHOES"}`, status: http.StatusBadRequest},
+		"own parent":     {body: `{"code":"SHOES","name":"Shoes","parent":"SHOES"}`, status: http.StatusBadRequest},
+		"cycle":          {body: `{"code":"SHOES","name":"Shoes","parent":"SNEAKERS"}`, err: models.ErrCategoryCycle, status: http.StatusBadRequest},
+		"not found":      {body: `{"code":"SHOES","name":"Shoes"}`, err: models.ErrCategoryNotFound, status: http.StatusNotFound},
+		"duplicate code": {body: `{"code":"BAGS","name":"Bags"}`, err: models.ErrCategoryCodeAlreadyExists, status: http.StatusConflict},
+		"repository":     {body: `{"code":"SHOES","name":"Shoes"}`, err: errors.New("db unavailable"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(&categoriesRepoMock{updateErr: tc.err})
+			req := httptest.NewRequest(http.MethodPut, "/categories/SHOES", bytes.NewBufferString(tc.body))
+			req.SetPathValue("code", "SHOES")
+			res := httptest.NewRecorder()
+
+			handler.HandlePut(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestHandleDeleteCategorySuccess(t *testing.T) {
+	t.Parallel()
+
+	mock := &categoriesRepoMock{}
+	handler := NewHandler(mock)
+
+	req := httptest.NewRequest(http.MethodDelete, "/categories/SHOES?reassign_to=ACCESSORIES", nil)
+	req.SetPathValue("code", "SHOES")
+	res := httptest.NewRecorder()
+
+	handler.HandleDelete(res, req)
+
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "SHOES", mock.capturedCode)
+	assert.Equal(t, "ACCESSORIES", mock.capturedReassign)
+}
+
+func TestHandleDeleteCategoryInUse(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&categoriesRepoMock{deleteErr: &models.CategoryInUseError{ProductCount: 2}})
+
+	req := httptest.NewRequest(http.MethodDelete, "/categories/SHOES", nil)
+	req.SetPathValue("code", "SHOES")
+	res := httptest.NewRecorder()
+
+	handler.HandleDelete(res, req)
+
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Contains(t, res.Body.String(), "category still has 2 products")
+}
+
+func TestHandleDeleteCategoryErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		target string
+		err    error
+		status int
+	}{
+		"reassign to itself": {target: "/categories/SHOES?reassign_to=SHOES", status: http.StatusBadRequest},
+		"not found":          {target: "/categories/SHOES", err: models.ErrCategoryNotFound, status: http.StatusNotFound},
+		"has children":       {target: "/categories/SHOES", err: models.ErrCategoryHasChildren, status: http.StatusConflict},
+		"missing target":     {target: "/categories/SHOES?reassign_to=NOPE", err: models.ErrReassignCategoryNotFound, status: http.StatusBadRequest},
+		"repository":         {target: "/categories/SHOES", err: errors.New("db unavailable"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(&categoriesRepoMock{deleteErr: tc.err})
+			req := httptest.NewRequest(http.MethodDelete, tc.target, nil)
+			req.SetPathValue("code", "SHOES")
+			res := httptest.NewRecorder()
+
+			handler.HandleDelete(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration above which queries are logged as slow.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// queryLogger is a gorm logger writing failed and slow queries to slog. The repositories run their
+// queries with the request context, so the entries carry the request ID of the HTTP request.
+type queryLogger struct {
+	logger *slog.Logger
+	level  logger.LogLevel
+}
+
+// newQueryLogger creates a query logger logging failed and slow queries.
+func newQueryLogger(l *slog.Logger) logger.Interface {
+	return queryLogger{logger: l, level: logger.Warn}
+}
+
+// LogMode returns a copy of the logger with the given level.
+func (l queryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	l.level = level
+	return l
+}
+
+// Info logs a gorm message at the info level.
+func (l queryLogger) Info(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Info {
+		l.log(ctx, slog.LevelInfo, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn logs a gorm message at the warn level.
+func (l queryLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Warn {
+		l.log(ctx, slog.LevelWarn, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error logs a gorm message at the error level.
+func (l queryLogger) Error(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Error {
+		l.log(ctx, slog.LevelError, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace logs a failed query, a missing record aside, or a slow one. At the info level every query is logged.
+func (l queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		l.logQuery(ctx, slog.LevelError, "query failed", elapsed, fc, slog.String("error", err.Error()))
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		l.logQuery(ctx, slog.LevelWarn, "slow query", elapsed, fc)
+	case l.level >= logger.Info:
+		l.logQuery(ctx, slog.LevelInfo, "query", elapsed, fc)
+	}
+}
+
+func (l queryLogger) logQuery(ctx context.Context, level slog.Level, msg string, elapsed time.Duration, fc func() (string, int64), attrs ...slog.Attr) {
+	sql, rows := fc()
+	attrs = append(attrs, slog.String("sql", sql), slog.Int64("rows", rows), slog.Duration("duration", elapsed))
+	l.log(ctx, level, msg, attrs...)
+}
+
+func (l queryLogger) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if id := requestid.FromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestQueryLogger(t *testing.T) {
+	query := func() (string, int64) { return "SELECT 1", 1 }
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	cases := map[string]struct {
+		level logger.LogLevel
+		begin time.Time
+		err   error
+		want  string
+	}{
+		"failed query":         {level: logger.Warn, begin: time.Now(), err: errors.New("db down"), want: "query failed"},
+		"slow query":           {level: logger.Warn, begin: time.Now().Add(-time.Second), want: "slow query"},
+		"fast query":           {level: logger.Warn, begin: time.Now()},
+		"missing record":       {level: logger.Warn, begin: time.Now(), err: gorm.ErrRecordNotFound},
+		"every query on info":  {level: logger.Info, begin: time.Now(), want: "query"},
+		"silent failed query":  {level: logger.Silent, begin: time.Now(), err: errors.New("db down")},
+		"slow query on errors": {level: logger.Error, begin: time.Now().Add(-time.Second)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := newQueryLogger(slog.New(slog.NewJSONHandler(&out, nil))).LogMode(tc.level)
+
+			l.Trace(ctx, tc.begin, query, tc.err)
+
+			if tc.want == "" {
+				assert.Empty(t, out.String())
+				return
+			}
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+			assert.Equal(t, tc.want, entry["msg"])
+			assert.Equal(t, "SELECT 1", entry["sql"])
+			assert.Equal(t, "req-1", entry["request_id"])
+		})
+	}
+}
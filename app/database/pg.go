@@ -1,27 +1,195 @@
 package database
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// New opens a postgres connection using gorm and returns a close function.
-func New(user, password, dbname, port string) (db *gorm.DB, close func() error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@localhost:%s/%s?sslmode=disable", user, password, port, dbname)
+// Config holds the postgres connection settings. A non-empty DSN is used as is and the
+// connection fields are ignored. Zero pool limits keep the database/sql defaults.
+// ReplicaDSNs are the connection strings of read replicas, which share the pool limits, and
+// PrimaryReadWindow is how long reads stay on the primary after a write. QueryTimeout bounds the
+// database queries of an HTTP request; zero leaves them unbounded.
+type Config struct {
+	DSN             string
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Name            string
+	SSLMode         string
+	SSLRootCert     string
+	SSLCert         string
+	SSLKey          string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	ReplicaDSNs       []string
+	PrimaryReadWindow time.Duration
+	QueryTimeout      time.Duration
+}
+
+// ConfigFromEnv reads the configuration from the environment. DATABASE_URL takes precedence over
+// the POSTGRES_HOST, POSTGRES_PORT, POSTGRES_USER, POSTGRES_PASSWORD, POSTGRES_DB, POSTGRES_SSLMODE,
+// POSTGRES_SSLROOTCERT, POSTGRES_SSLCERT and POSTGRES_SSLKEY variables. Pool limits are read from
+// POSTGRES_MAX_OPEN_CONNS, POSTGRES_MAX_IDLE_CONNS, POSTGRES_CONN_MAX_LIFETIME and
+// POSTGRES_CONN_MAX_IDLE_TIME, the last two being durations such as 5m. Read replicas are read from the
+// comma separated DATABASE_REPLICA_URLS, the primary read window from DATABASE_PRIMARY_READ_WINDOW and
+// the query timeout from POSTGRES_QUERY_TIMEOUT.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		DSN:         os.Getenv("DATABASE_URL"),
+		Host:        os.Getenv("POSTGRES_HOST"),
+		Port:        os.Getenv("POSTGRES_PORT"),
+		User:        os.Getenv("POSTGRES_USER"),
+		Password:    os.Getenv("POSTGRES_PASSWORD"),
+		Name:        os.Getenv("POSTGRES_DB"),
+		SSLMode:     os.Getenv("POSTGRES_SSLMODE"),
+		SSLRootCert: os.Getenv("POSTGRES_SSLROOTCERT"),
+		SSLCert:     os.Getenv("POSTGRES_SSLCERT"),
+		SSLKey:      os.Getenv("POSTGRES_SSLKEY"),
+	}
+
+	for name, target := range map[string]*int{
+		"POSTGRES_MAX_OPEN_CONNS": &cfg.MaxOpenConns,
+		"POSTGRES_MAX_IDLE_CONNS": &cfg.MaxIdleConns,
+	} {
+		if raw := os.Getenv(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 {
+				return Config{}, fmt.Errorf("invalid %s %q", name, raw)
+			}
+			*target = value
+		}
+	}
+
+	for name, target := range map[string]*time.Duration{
+		"POSTGRES_CONN_MAX_LIFETIME":   &cfg.ConnMaxLifetime,
+		"POSTGRES_CONN_MAX_IDLE_TIME":  &cfg.ConnMaxIdleTime,
+		"DATABASE_PRIMARY_READ_WINDOW": &cfg.PrimaryReadWindow,
+		"POSTGRES_QUERY_TIMEOUT":       &cfg.QueryTimeout,
+	} {
+		if raw := os.Getenv(name); raw != "" {
+			value, err := time.ParseDuration(raw)
+			if err != nil || value < 0 {
+				return Config{}, fmt.Errorf("invalid %s %q", name, raw)
+			}
+			*target = value
+		}
+	}
+
+	for _, dsn := range strings.Split(os.Getenv("DATABASE_REPLICA_URLS"), ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			cfg.ReplicaDSNs = append(cfg.ReplicaDSNs, dsn)
+		}
+	}
+
+	return cfg, nil
+}
+
+// ConnectionString returns the DSN, or a postgres URL built from the connection fields.
+// The host defaults to localhost, the port to 5432 and the SSL mode to disable.
+func (c Config) ConnectionString() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	host, port, sslMode := c.Host, c.Port, c.SSLMode
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "5432"
+	}
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	query := url.Values{"sslmode": {sslMode}}
+	for name, value := range map[string]string{"sslrootcert": c.SSLRootCert, "sslcert": c.SSLCert, "sslkey": c.SSLKey} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(host, port),
+		Path:     "/" + c.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// New opens a postgres connection using gorm, applies the pool limits and returns a close function.
+// Failed and slow queries are logged to the default slog logger.
+func New(cfg Config) (db *gorm.DB, close func() error, err error) {
+	db, err = gorm.Open(postgres.Open(cfg.ConnectionString()), &gorm.Config{Logger: newQueryLogger(slog.Default())})
 	if err != nil {
-		log.Fatalf("failed to connect database: %s", err)
+		return nil, nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to get database connection: %s", err)
+		return nil, nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	return db, sqlDB.Close, nil
+}
+
+// NewReplicas opens a connection to every read replica of the configuration with the same pool limits
+// as the primary, and returns a function closing them all.
+func NewReplicas(cfg Config) (replicas []*gorm.DB, close func() error, err error) {
+	closers := make([]func() error, 0, len(cfg.ReplicaDSNs))
+	closeAll := func() error {
+		var errs []error
+		for _, closer := range closers {
+			errs = append(errs, closer())
+		}
+
+		return errors.Join(errs...)
+	}
+
+	for i, dsn := range cfg.ReplicaDSNs {
+		replicaCfg := cfg
+		replicaCfg.DSN = dsn
+		replica, closer, err := New(replicaCfg)
+		if err != nil {
+			_ = closeAll()
+			return nil, nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+
+		replicas = append(replicas, replica)
+		closers = append(closers, closer)
 	}
 
-	return db, sqlDB.Close
+	return replicas, closeAll, nil
 }
@@ -0,0 +1,122 @@
+package exchangerates
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRateReaderWriter defines exchange rate operations consumed by the handler.
+type ExchangeRateReaderWriter interface {
+	ListExchangeRates(ctx context.Context) ([]models.ExchangeRate, error)
+	UpsertExchangeRate(ctx context.Context, rate models.ExchangeRate) (*models.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, base, quote string) error
+}
+
+// Handler exposes HTTP handlers for exchange rate endpoints.
+type Handler struct {
+	repo ExchangeRateReaderWriter
+}
+
+// NewHandler creates a new exchange rate handler.
+func NewHandler(repo ExchangeRateReaderWriter) *Handler {
+	return &Handler{repo: repo}
+}
+
+// ExchangeRateResponse represents an exchange rate returned by API responses.
+// One unit of base is worth rate units of quote.
+type ExchangeRateResponse struct {
+	Base  string          `json:"base"`
+	Quote string          `json:"quote"`
+	Rate  decimal.Decimal `json:"rate"`
+}
+
+// ListResponse contains the exchange rate list payload.
+type ListResponse struct {
+	Rates []ExchangeRateResponse `json:"rates"`
+}
+
+// PutExchangeRateRequest represents the exchange rate payload.
+type PutExchangeRateRequest struct {
+	Rate *decimal.Decimal `json:"rate"`
+}
+
+// HandleGet returns all exchange rates.
+func (h *Handler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.repo.ListExchangeRates(r.Context())
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to fetch exchange rates")
+		return
+	}
+
+	response := make([]ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		response[i] = newExchangeRateResponse(rate)
+	}
+
+	api.OKResponse(w, ListResponse{Rates: response})
+}
+
+// HandlePut creates or replaces the rate of a currency pair.
+func (h *Handler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	base, quote, ok := parsePair(r)
+	if !ok {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "invalid currency pair")
+		return
+	}
+
+	var req PutExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, "invalid request body")
+		return
+	}
+
+	if req.Rate == nil || !req.Rate.IsPositive() {
+		api.InvalidFieldResponse(w, r, "rate", api.FieldInvalid, "rate must be positive")
+		return
+	}
+
+	rate, err := h.repo.UpsertExchangeRate(r.Context(), models.ExchangeRate{BaseCurrency: base, QuoteCurrency: quote, Rate: *req.Rate})
+	if err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to save exchange rate")
+		return
+	}
+
+	api.OKResponse(w, newExchangeRateResponse(*rate))
+}
+
+// HandleDelete removes the rate of a currency pair.
+func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	base, quote, ok := parsePair(r)
+	if !ok {
+		api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidPathParameter, "invalid currency pair")
+		return
+	}
+
+	if err := h.repo.DeleteExchangeRate(r.Context(), base, quote); err != nil {
+		api.DomainErrorResponse(w, r, err, "failed to delete exchange rate")
+		return
+	}
+
+	api.NoContentResponse(w)
+}
+
+// parsePair reads the base and quote currencies from the request path.
+// It rejects malformed codes and pairs of the same currency.
+func parsePair(r *http.Request) (string, string, bool) {
+	base, baseOK := models.NormalizeCurrency(r.PathValue("base"))
+	quote, quoteOK := models.NormalizeCurrency(r.PathValue("quote"))
+	if !baseOK || !quoteOK || base == quote {
+		return "", "", false
+	}
+
+	return base, quote, true
+}
+
+func newExchangeRateResponse(rate models.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{Base: rate.BaseCurrency, Quote: rate.QuoteCurrency, Rate: rate.Rate}
+}
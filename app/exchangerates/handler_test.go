@@ -0,0 +1,153 @@
+package exchangerates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exchangeRatesRepoMock struct {
+	rates        []models.ExchangeRate
+	err          error
+	capturedRate *models.ExchangeRate
+	capturedPair [2]string
+}
+
+func (m *exchangeRatesRepoMock) ListExchangeRates(_ context.Context) ([]models.ExchangeRate, error) {
+	return m.rates, m.err
+}
+
+func (m *exchangeRatesRepoMock) UpsertExchangeRate(_ context.Context, rate models.ExchangeRate) (*models.ExchangeRate, error) {
+	m.capturedRate = &rate
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &rate, nil
+}
+
+func (m *exchangeRatesRepoMock) DeleteExchangeRate(_ context.Context, base, quote string) error {
+	m.capturedPair = [2]string{base, quote}
+	return m.err
+}
+
+func TestHandleGetExchangeRates(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&exchangeRatesRepoMock{
+		rates: []models.ExchangeRate{{BaseCurrency: "EUR", QuoteCurrency: "GBP", Rate: decimal.RequireFromString("0.85")}},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/exchange-rates", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var payload ListResponse
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+	require.Len(t, payload.Rates, 1)
+	assert.Equal(t, "EUR", payload.Rates[0].Base)
+	assert.True(t, decimal.RequireFromString("0.85").Equal(payload.Rates[0].Rate))
+}
+
+func TestHandleGetExchangeRatesError(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&exchangeRatesRepoMock{err: errors.New("db error")})
+	req := httptest.NewRequest(http.MethodGet, "/exchange-rates", nil)
+	res := httptest.NewRecorder()
+
+	handler.HandleGet(res, req)
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+}
+
+func TestHandlePutExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	mock := &exchangeRatesRepoMock{}
+	handler := NewHandler(mock)
+	req := httptest.NewRequest(http.MethodPut, "/exchange-rates/eur/usd", bytes.NewBufferString(`{"rate":"1.08"}`))
+	req.SetPathValue("base", "eur")
+	req.SetPathValue("quote", "usd")
+	res := httptest.NewRecorder()
+
+	handler.HandlePut(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	require.NotNil(t, mock.capturedRate)
+	assert.Equal(t, "EUR", mock.capturedRate.BaseCurrency)
+	assert.Equal(t, "USD", mock.capturedRate.QuoteCurrency)
+	assert.True(t, decimal.RequireFromString("1.08").Equal(mock.capturedRate.Rate))
+}
+
+func TestHandlePutExchangeRateErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		base   string
+		quote  string
+		body   string
+		err    error
+		status int
+	}{
+		"invalid pair":   {base: "EURO", quote: "USD", body: `{"rate":1}`, status: http.StatusBadRequest},
+		"same currency":  {base: "EUR", quote: "eur", body: `{"rate":1}`, status: http.StatusBadRequest},
+		"malformed json": {base: "EUR", quote: "USD", body: `{"rate":`, status: http.StatusBadRequest},
+		"missing rate":   {base: "EUR", quote: "USD", body: `{}`, status: http.StatusBadRequest},
+		"zero rate":      {base: "EUR", quote: "USD", body: `{"rate":0}`, status: http.StatusBadRequest},
+		"repository":     {base: "EUR", quote: "USD", body: `{"rate":1}`, err: errors.New("db error"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(&exchangeRatesRepoMock{err: tc.err})
+			req := httptest.NewRequest(http.MethodPut, "/exchange-rates/x/y", bytes.NewBufferString(tc.body))
+			req.SetPathValue("base", tc.base)
+			req.SetPathValue("quote", tc.quote)
+			res := httptest.NewRecorder()
+
+			handler.HandlePut(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+		})
+	}
+}
+
+func TestHandleDeleteExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err    error
+		status int
+	}{
+		"deleted":    {status: http.StatusNoContent},
+		"not found":  {err: models.ErrExchangeRateNotFound, status: http.StatusNotFound},
+		"repository": {err: errors.New("db error"), status: http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mock := &exchangeRatesRepoMock{err: tc.err}
+			handler := NewHandler(mock)
+			req := httptest.NewRequest(http.MethodDelete, "/exchange-rates/EUR/GBP", nil)
+			req.SetPathValue("base", "EUR")
+			req.SetPathValue("quote", "GBP")
+			res := httptest.NewRecorder()
+
+			handler.HandleDelete(res, req)
+
+			assert.Equal(t, tc.status, res.Code)
+			assert.Equal(t, [2]string{"EUR", "GBP"}, mock.capturedPair)
+		})
+	}
+}
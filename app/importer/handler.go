@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+)
+
+// maxImportBodySize bounds the size of an uploaded import file.
+const maxImportBodySize = 10 << 20
+
+// FileImporter defines the import operation consumed by the import handler.
+type FileImporter interface {
+	Import(ctx context.Context, src io.Reader, format Format, dryRun bool) (*Report, error)
+}
+
+// Handler exposes the HTTP handler for bulk catalog imports.
+type Handler struct {
+	importer FileImporter
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(importer FileImporter) *Handler {
+	return &Handler{importer: importer}
+}
+
+// HandlePost imports the CSV or NDJSON file sent as request body. The format is taken from the format
+// query parameter or else from the Content-Type header, and dry_run=true validates without persisting.
+// The report is returned with 200 when every row was valid and with 422 otherwise.
+func (h *Handler) HandlePost(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, ok := requestFormat(r)
+	if !ok {
+		if query.Has("format") {
+			api.InvalidQueryParameterResponse(w, r, "format")
+			return
+		}
+
+		api.ErrorResponse(w, r, http.StatusUnsupportedMediaType, api.CodeUnsupportedMediaType, "content type must be text/csv or application/x-ndjson")
+		return
+	}
+
+	var dryRun bool
+	if raw := query.Get("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			api.InvalidQueryParameterResponse(w, r, "dry_run")
+			return
+		}
+	}
+
+	report, err := h.importer.Import(r.Context(), http.MaxBytesReader(w, r.Body, maxImportBodySize), format, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			api.ErrorResponse(w, r, http.StatusRequestEntityTooLarge, api.CodePayloadTooLarge, "import file is too large")
+		case errors.Is(err, ErrInvalidInput):
+			api.ErrorResponse(w, r, http.StatusBadRequest, api.CodeInvalidRequestBody, err.Error())
+		default:
+			api.DomainErrorResponse(w, r, err, "failed to import catalog")
+		}
+		return
+	}
+
+	if len(report.Errors) > 0 {
+		api.JSONResponse(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	api.OKResponse(w, report)
+}
+
+// requestFormat returns the format of the request body. It reports false when the format is missing or unsupported.
+func requestFormat(r *http.Request) (Format, bool) {
+	if r.URL.Query().Has("format") {
+		return ParseFormat(r.URL.Query().Get("format"))
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, true
+	case "application/x-ndjson", "application/ndjson":
+		return FormatNDJSON, true
+	default:
+		return "", false
+	}
+}
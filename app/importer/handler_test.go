@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rowsImporterMock struct {
+	result         *models.ImportResult
+	err            error
+	called         bool
+	capturedRows   []models.ImportRow
+	capturedDryRun bool
+}
+
+func (m *rowsImporterMock) Import(_ context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportResult, error) {
+	m.called = true
+	m.capturedRows = rows
+	m.capturedDryRun = dryRun
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.result != nil {
+		return m.result, nil
+	}
+
+	return &models.ImportResult{Created: len(rows), Committed: !dryRun}, nil
+}
+
+func decimalPtr(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+const importCSV = "type,code,name,price,category\n" +
+	"category,BOOTS,Boots,,\n" +
+	"product,PROD100,Chelsea Boots,129.90,BOOTS\n"
+
+func newImportRequest(target, contentType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestImportHandlePost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("imports csv", func(t *testing.T) {
+		mock := &rowsImporterMock{}
+		handler := NewHandler(New(mock))
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newImportRequest("/catalog/import", "text/csv; charset=utf-8", importCSV))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.False(t, mock.capturedDryRun)
+		require.Len(t, mock.capturedRows, 2)
+
+		var payload Report
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.Equal(t, Report{Committed: true, Rows: 2, Created: 2, Errors: []RowError{}}, payload)
+	})
+
+	t.Run("format query overrides content type", func(t *testing.T) {
+		mock := &rowsImporterMock{}
+		handler := NewHandler(New(mock))
+		res := httptest.NewRecorder()
+
+		body := `{"type":"category","code":"BOOTS","name":"Boots"}`
+		handler.HandlePost(res, newImportRequest("/catalog/import?format=ndjson&dry_run=true", "text/plain", body))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.True(t, mock.capturedDryRun)
+		require.Len(t, mock.capturedRows, 1)
+
+		var payload Report
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.True(t, payload.DryRun)
+		assert.False(t, payload.Committed)
+	})
+
+	t.Run("invalid rows force a dry run and merge database errors", func(t *testing.T) {
+		mock := &rowsImporterMock{result: &models.ImportResult{
+			Created: 1,
+			Errors:  []models.ImportRowError{{Line: 2, Message: "parent category not found"}},
+		}}
+		handler := NewHandler(New(mock))
+		res := httptest.NewRecorder()
+
+		body := importCSV + "product,PROD101,Boots,-5,BOOTS\n"
+		handler.HandlePost(res, newImportRequest("/catalog/import", "text/csv", body))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		assert.True(t, mock.capturedDryRun)
+
+		var payload Report
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+		assert.False(t, payload.DryRun)
+		assert.False(t, payload.Committed)
+		assert.Equal(t, 3, payload.Rows)
+		assert.Equal(t, []RowError{
+			{Line: 2, Message: "parent category not found"},
+			{Line: 4, Message: "price must not be negative"},
+		}, payload.Errors)
+	})
+
+	t.Run("request errors", func(t *testing.T) {
+		cases := []struct {
+			name        string
+			target      string
+			contentType string
+			body        string
+			status      int
+		}{
+			{name: "unsupported content type", target: "/catalog/import", contentType: "application/json", status: http.StatusUnsupportedMediaType},
+			{name: "missing content type", target: "/catalog/import", status: http.StatusUnsupportedMediaType},
+			{name: "invalid format", target: "/catalog/import?format=xlsx", contentType: "text/csv", status: http.StatusBadRequest},
+			{name: "invalid dry run", target: "/catalog/import?dry_run=maybe", contentType: "text/csv", status: http.StatusBadRequest},
+			{name: "invalid header", target: "/catalog/import", contentType: "text/csv", body: "code,colour\n", status: http.StatusBadRequest},
+			{name: "too large", target: "/catalog/import?format=ndjson", body: strings.Repeat("\n", maxImportBodySize+1), status: http.StatusRequestEntityTooLarge},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				mock := &rowsImporterMock{}
+				handler := NewHandler(New(mock))
+				res := httptest.NewRecorder()
+
+				handler.HandlePost(res, newImportRequest(tc.target, tc.contentType, tc.body))
+
+				assert.Equal(t, tc.status, res.Code)
+				assert.False(t, mock.called)
+			})
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		handler := NewHandler(New(&rowsImporterMock{err: errors.New("db down")}))
+		res := httptest.NewRecorder()
+
+		handler.HandlePost(res, newImportRequest("/catalog/import", "text/csv", importCSV))
+
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.JSONEq(t, `{"type":"urn:problem:internal_error","title":"Internal error","status":500,"code":"internal_error","detail":"failed to import catalog","instance":"/catalog/import"}`, res.Body.String())
+	})
+}
@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// RowsImporter defines the bulk upsert consumed by the importer.
+type RowsImporter interface {
+	Import(ctx context.Context, rows []models.ImportRow, dryRun bool) (*models.ImportResult, error)
+}
+
+// Importer parses import files and upserts their rows.
+type Importer struct {
+	repo RowsImporter
+}
+
+// New creates a new Importer.
+func New(repo RowsImporter) *Importer {
+	return &Importer{repo: repo}
+}
+
+// Report summarizes an import. Created and Updated count the rows that were, or in a dry run
+// or a failed import would have been, applied. Nothing is persisted unless Committed is true.
+type Report struct {
+	DryRun    bool       `json:"dry_run"`
+	Committed bool       `json:"committed"`
+	Rows      int        `json:"rows"`
+	Created   int        `json:"created"`
+	Updated   int        `json:"updated"`
+	Errors    []RowError `json:"errors"`
+}
+
+// RowError reports why the row at a line of the import file was rejected.
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Import parses src and upserts its rows in a single transaction. When any row is invalid the valid
+// rows are still checked against the database in a dry run, so that every row error is reported at once.
+func (i *Importer) Import(ctx context.Context, src io.Reader, format Format, dryRun bool) (*Report, error) {
+	rows, rowErrors, err := Parse(src, format)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(rows) + len(rowErrors)
+	result, err := i.repo.Import(ctx, rows, dryRun || len(rowErrors) > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rowErrors = append(rowErrors, result.Errors...)
+	sort.SliceStable(rowErrors, func(a, b int) bool {
+		return rowErrors[a].Line < rowErrors[b].Line
+	})
+
+	report := &Report{
+		DryRun:    dryRun,
+		Committed: result.Committed,
+		Rows:      total,
+		Created:   result.Created,
+		Updated:   result.Updated,
+		Errors:    make([]RowError, len(rowErrors)),
+	}
+	for n, rowError := range rowErrors {
+		report.Errors[n] = RowError{Line: rowError.Line, Message: rowError.Message}
+	}
+
+	return report, nil
+}
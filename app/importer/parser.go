@@ -0,0 +1,284 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxCodeLength        = 32
+	maxVariantNameLength = 256
+	maxNDJSONLineSize    = 1 << 20
+)
+
+// ErrInvalidInput indicates that an import file cannot be read at all, as opposed to a file with invalid rows.
+var ErrInvalidInput = errors.New("invalid import input")
+
+// Format identifies the encoding of an import file.
+type Format string
+
+const (
+	// FormatCSV is a comma separated file whose first line names the columns.
+	FormatCSV Format = "csv"
+	// FormatNDJSON is a file holding one JSON object per line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat returns the format with the given name, ignoring case.
+func ParseFormat(name string) (Format, bool) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(name))); format {
+	case FormatCSV, FormatNDJSON:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+// Record is a raw import row. Type selects the entity: categories use code, name and parent;
+// products use code, name, description, price, currency and category; variants use product, sku,
+// name and an optional price override.
+type Record struct {
+	Type        string           `json:"type"`
+	Code        string           `json:"code,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	Category    string           `json:"category,omitempty"`
+	Parent      string           `json:"parent,omitempty"`
+	Product     string           `json:"product,omitempty"`
+	SKU         string           `json:"sku,omitempty"`
+}
+
+// csvColumns lists the columns accepted in a CSV header, in the order they are written.
+var csvColumns = []string{"type", "code", "name", "description", "price", "currency", "category", "parent", "product", "sku"}
+
+// Parse reads and validates every row of an import file. Rows are numbered by their line in the file.
+// Invalid rows are reported as row errors and left out of the returned rows. Files that cannot be read,
+// such as a CSV file without a valid header, fail with ErrInvalidInput.
+func Parse(src io.Reader, format Format) ([]models.ImportRow, []models.ImportRowError, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(src)
+	case FormatNDJSON:
+		return parseNDJSON(src)
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidInput, format)
+	}
+}
+
+func parseCSV(src io.Reader) ([]models.ImportRow, []models.ImportRowError, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: missing header", ErrInvalidInput)
+		}
+
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidInput, name)
+		}
+
+		if _, ok := columns[name]; ok {
+			return nil, nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidInput, name)
+		}
+
+		columns[name] = i
+	}
+
+	if _, ok := columns["type"]; !ok {
+		return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidInput, "type")
+	}
+
+	var rows []models.ImportRow
+	var rowErrors []models.ImportRowError
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(fields) != len(header) {
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Line:    line,
+				Message: fmt.Sprintf("expected %d columns, got %d", len(header), len(fields)),
+			})
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return fields[i]
+			}
+
+			return ""
+		}
+
+		record := Record{
+			Type:        field("type"),
+			Code:        field("code"),
+			Name:        field("name"),
+			Description: field("description"),
+			Currency:    field("currency"),
+			Category:    field("category"),
+			Parent:      field("parent"),
+			Product:     field("product"),
+			SKU:         field("sku"),
+		}
+		if raw := strings.TrimSpace(field("price")); raw != "" {
+			price, err := decimal.NewFromString(raw)
+			if err != nil {
+				rowErrors = append(rowErrors, models.ImportRowError{Line: line, Message: "price must be a number"})
+				continue
+			}
+			record.Price = &price
+		}
+
+		rows, rowErrors = appendRecord(rows, rowErrors, line, record)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseNDJSON(src io.Reader) ([]models.ImportRow, []models.ImportRowError, error) {
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+
+	var rows []models.ImportRow
+	var rowErrors []models.ImportRowError
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		var record Record
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil || decoder.More() {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: line, Message: "invalid JSON object"})
+			continue
+		}
+
+		rows, rowErrors = appendRecord(rows, rowErrors, line, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	return rows, rowErrors, nil
+}
+
+// appendRecord validates a record and appends it to rows, or its error to rowErrors.
+func appendRecord(rows []models.ImportRow, rowErrors []models.ImportRowError, line int, record Record) ([]models.ImportRow, []models.ImportRowError) {
+	row, message := validateRecord(record)
+	if message != "" {
+		return rows, append(rowErrors, models.ImportRowError{Line: line, Message: message})
+	}
+
+	row.Line = line
+	return append(rows, row), rowErrors
+}
+
+// validateRecord converts a record to an import row. It returns a message describing the first invalid field.
+func validateRecord(record Record) (models.ImportRow, string) {
+	code := strings.TrimSpace(record.Code)
+	name := strings.TrimSpace(record.Name)
+
+	switch kind := models.ImportRowKind(strings.ToLower(strings.TrimSpace(record.Type))); kind {
+	case models.ImportCategory:
+		if code == "" || name == "" {
+			return models.ImportRow{}, "code and name are required"
+		}
+
+		return models.ImportRow{
+			Kind:     kind,
+			Category: models.CategoryInput{Code: code, Name: name, ParentCode: strings.TrimSpace(record.Parent)},
+		}, ""
+	case models.ImportProduct:
+		category := strings.TrimSpace(record.Category)
+		if code == "" || record.Price == nil || category == "" {
+			return models.ImportRow{}, "code, price and category are required"
+		}
+
+		if utf8.RuneCountInString(code) > maxCodeLength {
+			return models.ImportRow{}, "code must be at most 32 characters"
+		}
+
+		if models.IsReservedProductCode(code) {
+			return models.ImportRow{}, "code is reserved"
+		}
+
+		if record.Price.IsNegative() {
+			return models.ImportRow{}, "price must not be negative"
+		}
+
+		var currency string
+		if strings.TrimSpace(record.Currency) != "" {
+			var ok bool
+			if currency, ok = models.NormalizeCurrency(record.Currency); !ok {
+				return models.ImportRow{}, "currency must be a 3-letter code"
+			}
+		}
+
+		return models.ImportRow{
+			Kind: kind,
+			Product: models.ProductInput{
+				Code:         code,
+				Name:         name,
+				Description:  strings.TrimSpace(record.Description),
+				Price:        *record.Price,
+				Currency:     currency,
+				CategoryCode: category,
+			},
+		}, ""
+	case models.ImportVariant:
+		product := strings.TrimSpace(record.Product)
+		sku := strings.TrimSpace(record.SKU)
+		if product == "" || sku == "" || name == "" {
+			return models.ImportRow{}, "product, sku and name are required"
+		}
+
+		if utf8.RuneCountInString(sku) > maxCodeLength || utf8.RuneCountInString(name) > maxVariantNameLength {
+			return models.ImportRow{}, "sku or name is too long"
+		}
+
+		if record.Price != nil && record.Price.IsNegative() {
+			return models.ImportRow{}, "price must not be negative"
+		}
+
+		return models.ImportRow{
+			Kind:        kind,
+			ProductCode: product,
+			Variant:     models.VariantInput{SKU: sku, Name: name, Price: record.Price},
+		}, ""
+	default:
+		return models.ImportRow{}, "type must be category, product or variant"
+	}
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	format, ok := ParseFormat(" CSV ")
+	assert.True(t, ok)
+	assert.Equal(t, FormatCSV, format)
+
+	format, ok = ParseFormat("ndjson")
+	assert.True(t, ok)
+	assert.Equal(t, FormatNDJSON, format)
+
+	_, ok = ParseFormat("xlsx")
+	assert.False(t, ok)
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses every kind", func(t *testing.T) {
+		src := strings.Join([]string{
+			"type,code,name,description,price,currency,category,parent,product,sku",
+			"category,BOOTS,Boots,,,,,SHOES,,",
+			"product,PROD100, Chelsea Boots ,\"Leather, black\",129.90,eur,BOOTS,,,",
+			"variant,,Size 42,,,,,,PROD100,SKU100A",
+			"variant,,Size 43,,139.90,,,,PROD100,SKU100B",
+		}, "\n")
+
+		rows, rowErrors, err := Parse(strings.NewReader(src), FormatCSV)
+
+		require.NoError(t, err)
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 4)
+
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, models.ImportCategory, rows[0].Kind)
+		assert.Equal(t, models.CategoryInput{Code: "BOOTS", Name: "Boots", ParentCode: "SHOES"}, rows[0].Category)
+
+		assert.Equal(t, 3, rows[1].Line)
+		assert.Equal(t, models.ImportProduct, rows[1].Kind)
+		assert.Equal(t, "PROD100", rows[1].Product.Code)
+		assert.Equal(t, "Chelsea Boots", rows[1].Product.Name)
+		assert.Equal(t, "Leather, black", rows[1].Product.Description)
+		assert.Equal(t, "129.9", rows[1].Product.Price.String())
+		assert.Equal(t, "EUR", rows[1].Product.Currency)
+		assert.Equal(t, "BOOTS", rows[1].Product.CategoryCode)
+
+		assert.Equal(t, models.ImportVariant, rows[2].Kind)
+		assert.Equal(t, "PROD100", rows[2].ProductCode)
+		assert.Equal(t, "SKU100A", rows[2].Variant.SKU)
+		assert.Nil(t, rows[2].Variant.Price)
+		require.NotNil(t, rows[3].Variant.Price)
+		assert.Equal(t, "139.9", rows[3].Variant.Price.String())
+	})
+
+	t.Run("columns may be omitted and reordered", func(t *testing.T) {
+		src := "sku,product,name,type\nSKU100A,PROD100,Size 42,variant\n"
+
+		rows, rowErrors, err := Parse(strings.NewReader(src), FormatCSV)
+
+		require.NoError(t, err)
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "SKU100A", rows[0].Variant.SKU)
+	})
+
+	t.Run("reports invalid rows", func(t *testing.T) {
+		src := strings.Join([]string{
+			"type,code,name,price,category",
+			"product,PROD100,Boots,abc,BOOTS",
+			"product,PROD101,Boots,-1,BOOTS",
+			"product,PROD102,Boots,10,",
+			"category,BOOTS",
+			"brand,ACME,Acme,,",
+			"product,PROD103,Boots,10,BOOTS",
+		}, "\n")
+
+		rows, rowErrors, err := Parse(strings.NewReader(src), FormatCSV)
+
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 7, rows[0].Line)
+		assert.Equal(t, []models.ImportRowError{
+			{Line: 2, Message: "price must be a number"},
+			{Line: 3, Message: "price must not be negative"},
+			{Line: 4, Message: "code, price and category are required"},
+			{Line: 5, Message: "expected 5 columns, got 2"},
+			{Line: 6, Message: "type must be category, product or variant"},
+		}, rowErrors)
+	})
+
+	t.Run("rejects invalid headers", func(t *testing.T) {
+		for _, src := range []string{"", "code,name\n", "type,colour\n", "type,code,code\n"} {
+			_, _, err := Parse(strings.NewReader(src), FormatCSV)
+			assert.ErrorIs(t, err, ErrInvalidInput, src)
+		}
+	})
+}
+
+func TestParseNDJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses objects and skips blank lines", func(t *testing.T) {
+		src := strings.Join([]string{
+			`{"type":"product","code":"PROD100","name":"Boots","price":"129.90","category":"BOOTS"}`,
+			``,
+			`{"type":"variant","product":"PROD100","sku":"SKU100A","name":"Size 42","price":139.9}`,
+		}, "\n")
+
+		rows, rowErrors, err := Parse(strings.NewReader(src), FormatNDJSON)
+
+		require.NoError(t, err)
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 2)
+		assert.Equal(t, 1, rows[0].Line)
+		assert.Equal(t, "129.9", rows[0].Product.Price.String())
+		assert.Equal(t, 3, rows[1].Line)
+		require.NotNil(t, rows[1].Variant.Price)
+		assert.Equal(t, "139.9", rows[1].Variant.Price.String())
+	})
+
+	t.Run("reports invalid rows", func(t *testing.T) {
+		src := strings.Join([]string{
+			`{"type":"product",`,
+			`{"type":"category","code":"BOOTS","name":"Boots","colour":"red"}`,
+			`{"type":"variant","product":"PROD100","sku":"SKU100A"}`,
+			`{"type":"category","code":"BOOTS","name":"Boots"} {}`,
+		}, "\n")
+
+		rows, rowErrors, err := Parse(strings.NewReader(src), FormatNDJSON)
+
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+		assert.Equal(t, []models.ImportRowError{
+			{Line: 1, Message: "invalid JSON object"},
+			{Line: 2, Message: "invalid JSON object"},
+			{Line: 3, Message: "product, sku and name are required"},
+			{Line: 4, Message: "invalid JSON object"},
+		}, rowErrors)
+	})
+
+	t.Run("rejects oversized lines", func(t *testing.T) {
+		_, _, err := Parse(strings.NewReader(strings.Repeat("x", maxNDJSONLineSize+1)), FormatNDJSON)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
+
+func TestValidateRecordLimits(t *testing.T) {
+	t.Parallel()
+
+	_, message := validateRecord(Record{Type: "product", Code: strings.Repeat("A", 33), Price: decimalPtr("1"), Category: "BOOTS"})
+	assert.Equal(t, "code must be at most 32 characters", message)
+
+	_, message = validateRecord(Record{Type: "product", Code: strings.Repeat("Ä", 32), Price: decimalPtr("1"), Category: "BOOTS"})
+	assert.Empty(t, message)
+
+	_, message = validateRecord(Record{Type: "product", Code: "export", Price: decimalPtr("1"), Category: "BOOTS"})
+	assert.Equal(t, "code is reserved", message)
+
+	_, message = validateRecord(Record{Type: "product", Code: "PROD100", Price: decimalPtr("1"), Currency: "euro", Category: "BOOTS"})
+	assert.Equal(t, "currency must be a 3-letter code", message)
+
+	_, message = validateRecord(Record{Type: "variant", Product: "PROD100", SKU: strings.Repeat("S", 33), Name: "Size 42"})
+	assert.Equal(t, "sku or name is too long", message)
+
+	_, message = validateRecord(Record{Type: "variant", Product: "PROD100", SKU: strings.Repeat("Ä", 32), Name: strings.Repeat("é", maxVariantNameLength)})
+	assert.Empty(t, message)
+
+	_, message = validateRecord(Record{Type: "variant", Product: "PROD100", SKU: "SKU100A", Name: "Size 42", Price: decimalPtr("-1")})
+	assert.Equal(t, "price must not be negative", message)
+
+	_, message = validateRecord(Record{Type: "category", Code: "BOOTS"})
+	assert.Equal(t, "code and name are required", message)
+}
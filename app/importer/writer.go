@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Writer encodes records in an import format, so that exported files can be imported back.
+// Output is buffered until Flush is called or the buffer fills up.
+type Writer struct {
+	format        Format
+	csv           *csv.Writer
+	json          *json.Encoder
+	headerWritten bool
+}
+
+// NewWriter creates a writer encoding records to w in the given format.
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &Writer{format: format, csv: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &Writer{format: format, json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// WriteProduct writes a product row followed by one variant row per variant of the product.
+func (w *Writer) WriteProduct(product models.Product) error {
+	price := product.Price
+	if err := w.Write(Record{
+		Type:        string(models.ImportProduct),
+		Code:        product.Code,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       &price,
+		Currency:    product.Currency,
+		Category:    product.Category.Code,
+	}); err != nil {
+		return err
+	}
+
+	for _, variant := range product.Variants {
+		if err := w.Write(Record{
+			Type:    string(models.ImportVariant),
+			Name:    variant.Name,
+			Price:   variant.Price,
+			Product: product.Code,
+			SKU:     variant.SKU,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Write writes a single record. The CSV header is written before the first record.
+func (w *Writer) Write(record Record) error {
+	if w.format == FormatNDJSON {
+		return w.json.Encode(record)
+	}
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	var price string
+	if record.Price != nil {
+		price = record.Price.String()
+	}
+
+	return w.csv.Write([]string{
+		record.Type, record.Code, record.Name, record.Description, price,
+		record.Currency, record.Category, record.Parent, record.Product, record.SKU,
+	})
+}
+
+// Flush writes any buffered output, including the CSV header when no record was written.
+func (w *Writer) Flush() error {
+	if w.format == FormatNDJSON {
+		return nil
+	}
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+	return w.csv.Write(csvColumns)
+}
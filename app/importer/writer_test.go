@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportedProduct() models.Product {
+	return models.Product{
+		Code:        "PROD100",
+		Name:        "Chelsea Boots",
+		Description: "Leather, black",
+		Price:       decimal.RequireFromString("129.90"),
+		Currency:    "EUR",
+		Category:    models.Category{Code: "BOOTS"},
+		Variants: []models.Variant{
+			{SKU: "SKU100A", Name: "Size 42"},
+			{SKU: "SKU100B", Name: "Size 43", Price: decimalPtr("139.90")},
+		},
+	}
+}
+
+func TestWriterCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteProduct(exportedProduct()))
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, strings.Join([]string{
+		"type,code,name,description,price,currency,category,parent,product,sku",
+		`product,PROD100,Chelsea Boots,"Leather, black",129.9,EUR,BOOTS,,,`,
+		"variant,,Size 42,,,,,,PROD100,SKU100A",
+		"variant,,Size 43,,139.9,,,,PROD100,SKU100B",
+		"",
+	}, "\n"), buf.String())
+
+	rows, rowErrors, err := Parse(&buf, FormatCSV)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Len(t, rows, 3)
+}
+
+func TestWriterNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatNDJSON)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteProduct(exportedProduct()))
+	require.NoError(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"type":"product","code":"PROD100","name":"Chelsea Boots","description":"Leather, black","price":"129.9","currency":"EUR","category":"BOOTS"}`, lines[0])
+	assert.JSONEq(t, `{"type":"variant","name":"Size 42","product":"PROD100","sku":"SKU100A"}`, lines[1])
+
+	rows, rowErrors, err := Parse(&buf, FormatNDJSON)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Len(t, rows, 3)
+}
+
+func TestWriterEmptyCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, FormatCSV)
+	require.NoError(t, err)
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, "type,code,name,description,price,currency,category,parent,product,sku\n", buf.String())
+
+	_, err = NewWriter(&buf, Format("xlsx"))
+	assert.Error(t, err)
+}
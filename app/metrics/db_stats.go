@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"sync"
+)
+
+// DBStats exposes the connection pool statistics of database handles.
+type DBStats struct {
+	mu    sync.Mutex
+	pools []namedPool
+}
+
+type namedPool struct {
+	name string
+	db   *sql.DB
+}
+
+// dbStatsMetric describes a metric read from sql.DBStats.
+type dbStatsMetric struct {
+	name, help, kind string
+	value            func(sql.DBStats) float64
+}
+
+var dbStatsMetrics = []dbStatsMetric{
+	{"db_pool_max_open_connections", "Maximum number of open connections to the database.", "gauge", func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+	{"db_pool_open_connections", "Number of established connections, in use or idle.", "gauge", func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+	{"db_pool_in_use_connections", "Number of connections currently in use.", "gauge", func(s sql.DBStats) float64 { return float64(s.InUse) }},
+	{"db_pool_idle_connections", "Number of idle connections.", "gauge", func(s sql.DBStats) float64 { return float64(s.Idle) }},
+	{"db_pool_wait_count_total", "Number of connections waited for.", "counter", func(s sql.DBStats) float64 { return float64(s.WaitCount) }},
+	{"db_pool_wait_duration_seconds_total", "Time blocked waiting for a new connection.", "counter", func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }},
+	{"db_pool_max_idle_closed_total", "Number of connections closed due to the idle connection limit.", "counter", func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }},
+	{"db_pool_max_idle_time_closed_total", "Number of connections closed due to the maximum idle time.", "counter", func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }},
+	{"db_pool_max_lifetime_closed_total", "Number of connections closed due to the maximum lifetime.", "counter", func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }},
+}
+
+// NewDBStats registers the connection pool statistics. Pools are added with Add.
+func NewDBStats(r *Registry) *DBStats {
+	s := &DBStats{}
+	r.register(s)
+	return s
+}
+
+// Add exposes the statistics of db under the pool label name, such as "primary".
+func (s *DBStats) Add(name string, db *sql.DB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pools = append(s.pools, namedPool{name: name, db: db})
+}
+
+func (s *DBStats) collect(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]sql.DBStats, len(s.pools))
+	for i, pool := range s.pools {
+		stats[i] = pool.db.Stats()
+	}
+
+	for _, metric := range dbStatsMetrics {
+		writeHeader(w, metric.name, metric.help, metric.kind)
+		for i, pool := range s.pools {
+			writeSample(w, metric.name, []string{"pool"}, []string{pool.name}, metric.value(stats[i]))
+		}
+	}
+}
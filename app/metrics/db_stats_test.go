@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBStats(t *testing.T) {
+	primary := openDryRunDB(t)
+	sqlDB, err := primary.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(7)
+
+	registry := NewRegistry()
+	NewDBStats(registry).Add("primary", sqlDB)
+
+	var out strings.Builder
+	_, err = registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "# TYPE db_pool_max_open_connections gauge\n")
+	assert.Contains(t, out.String(), `db_pool_max_open_connections{pool="primary"} 7`)
+	assert.Contains(t, out.String(), `db_pool_open_connections{pool="primary"} 0`)
+	assert.Contains(t, out.String(), "# TYPE db_pool_wait_count_total counter\n")
+	assert.Contains(t, out.String(), `db_pool_wait_duration_seconds_total{pool="primary"} 0`)
+}
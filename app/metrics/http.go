@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels the requests matching no route, so that unknown paths do not create series.
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics counts the HTTP requests and their latency per route pattern and status.
+type HTTPMetrics struct {
+	requests *CounterVec
+	duration *HistogramVec
+}
+
+// NewHTTPMetrics registers the HTTP request metrics.
+func NewHTTPMetrics(r *Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: r.NewCounterVec("http_requests_total", "Number of HTTP requests by route pattern and status.", "route", "status"),
+		duration: r.NewHistogramVec("http_request_duration_seconds", "Latency of HTTP requests by route pattern and status.", DefaultBuckets, "route", "status"),
+	}
+}
+
+// Observe records a request served by the route pattern, such as "GET /catalog/{code}".
+// An empty route records a request matching no route.
+func (m *HTTPMetrics) Observe(route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	code := strconv.Itoa(status)
+	m.requests.Inc(route, code)
+	m.duration.Observe(elapsed.Seconds(), route, code)
+}
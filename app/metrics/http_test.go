@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	registry := NewRegistry()
+	m := NewHTTPMetrics(registry)
+
+	m.Observe("GET /catalog/{code}", http.StatusOK, 30*time.Millisecond)
+	m.Observe("GET /catalog/{code}", http.StatusNotFound, 2*time.Millisecond)
+	m.Observe("", http.StatusNotFound, time.Millisecond)
+
+	var out strings.Builder
+	_, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `http_requests_total{route="GET /catalog/{code}",status="200"} 1`)
+	assert.Contains(t, out.String(), `http_requests_total{route="GET /catalog/{code}",status="404"} 1`)
+	assert.Contains(t, out.String(), `http_requests_total{route="unmatched",status="404"} 1`)
+	assert.Contains(t, out.String(), `http_request_duration_seconds_bucket{route="GET /catalog/{code}",status="200",le="0.025"} 0`)
+	assert.Contains(t, out.String(), `http_request_duration_seconds_bucket{route="GET /catalog/{code}",status="200",le="0.05"} 1`)
+	assert.Contains(t, out.String(), `http_request_duration_seconds_count{route="GET /catalog/{code}",status="200"} 1`)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("requests_total", "Number of requests.", "route", "status")
+	duration := registry.NewHistogramVec("request_duration_seconds", "Latency of requests.", []float64{0.1, 1}, "route")
+
+	requests.Inc("GET /catalog", "200")
+	requests.Add(2, "GET /catalog", "200")
+	requests.Inc("GET /catalog/{code}", "404")
+	duration.Observe(0.05, "GET /catalog")
+	duration.Observe(0.5, "GET /catalog")
+	duration.Observe(2, "GET /catalog")
+
+	var out strings.Builder
+	n, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	expected := `# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{route="GET /catalog",status="200"} 3
+requests_total{route="GET /catalog/{code}",status="404"} 1
+# HELP request_duration_seconds Latency of requests.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{route="GET /catalog",le="0.1"} 1
+request_duration_seconds_bucket{route="GET /catalog",le="1"} 2
+request_duration_seconds_bucket{route="GET /catalog",le="+Inf"} 3
+request_duration_seconds_sum{route="GET /catalog"} 2.55
+request_duration_seconds_count{route="GET /catalog"} 3
+`
+	assert.Equal(t, expected, out.String())
+	assert.Equal(t, int64(len(expected)), n)
+}
+
+func TestRegistryEscaping(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("escaped_total", "Help with \\ and\nnewline.", "value").Inc("quote \" backslash \\ newline \n")
+	registry.NewCounterVec("unlabelled_total", "No labels.").Inc()
+
+	var out strings.Builder
+	_, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	expected := `# HELP escaped_total Help with \\ and\nnewline.
+# TYPE escaped_total counter
+escaped_total{value="quote \" backslash \\ newline \n"} 1
+# HELP unlabelled_total No labels.
+# TYPE unlabelled_total counter
+unlabelled_total 1
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRegistryHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.NewCounterVec("requests_total", "Number of requests.").Inc()
+	res := httptest.NewRecorder()
+
+	registry.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", res.Header().Get("Content-Type"))
+	assert.Contains(t, res.Body.String(), "requests_total 1\n")
+}
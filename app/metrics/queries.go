@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// unknownMethod labels the queries not run by a repository method.
+const unknownMethod = "unknown"
+
+// queryStartKey stores the start time of a query in its gorm statement.
+const queryStartKey = "metrics:query_start"
+
+// MethodFunc returns the repository method running the queries of a context, such as
+// "ProductsRepository.ListProducts". It reports false for queries not run by a repository method.
+type MethodFunc func(ctx context.Context) (string, bool)
+
+// QueryMetrics is a gorm plugin recording the duration and the errors of queries per repository method.
+// The repositories label their queries with the method running them through the query context.
+type QueryMetrics struct {
+	method   MethodFunc
+	duration *HistogramVec
+	errors   *CounterVec
+}
+
+// NewQueryMetrics registers the query metrics of the repositories whose methods are read with method.
+func NewQueryMetrics(r *Registry, method MethodFunc) *QueryMetrics {
+	return &QueryMetrics{
+		method:   method,
+		duration: r.NewHistogramVec("db_query_duration_seconds", "Duration of database queries by repository method.", DefaultBuckets, "method"),
+		errors:   r.NewCounterVec("db_query_errors_total", "Number of failed database queries by repository method.", "method"),
+	}
+}
+
+// Name implements gorm.Plugin.
+func (m *QueryMetrics) Name() string {
+	return "metrics:queries"
+}
+
+// Initialize implements gorm.Plugin by registering timing callbacks around every kind of query.
+func (m *QueryMetrics) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", m.before),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", m.after),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", m.before),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", m.after),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", m.before),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", m.after),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", m.before),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", m.after),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", m.before),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", m.after),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", m.before),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", m.after),
+	)
+}
+
+func (m *QueryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (m *QueryMetrics) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(queryStartKey)
+	if !ok {
+		return
+	}
+
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+
+	method, ok := m.method(db.Statement.Context)
+	if !ok {
+		method = unknownMethod
+	}
+
+	m.duration.Observe(time.Since(start).Seconds(), method)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		m.errors.Inc(method)
+	}
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func openDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	return db
+}
+
+type testProduct struct {
+	ID   uint
+	Code string
+}
+
+// testMethodKey is the context key of the method labelling the queries of testRepository.
+type testMethodKey struct{}
+
+func testMethod(ctx context.Context) (string, bool) {
+	method, ok := ctx.Value(testMethodKey{}).(string)
+	return method, ok
+}
+
+// testRepository stands in for a repository labelling its queries.
+type testRepository struct {
+	db *gorm.DB
+}
+
+func (r *testRepository) ListProducts(ctx context.Context) error {
+	ctx = context.WithValue(ctx, testMethodKey{}, "testRepository.ListProducts")
+
+	var products []testProduct
+	return r.find(ctx, &products)
+}
+
+func (r *testRepository) UpdateProduct(ctx context.Context) error {
+	ctx = context.WithValue(ctx, testMethodKey{}, "testRepository.UpdateProduct")
+
+	update := func(tx *gorm.DB) error {
+		return tx.Model(&testProduct{}).Where("code = ?", "PROD001").Update("code", "PROD002").Error
+	}
+
+	return update(r.db.WithContext(ctx))
+}
+
+func (r *testRepository) find(ctx context.Context, dest any) error {
+	return r.db.WithContext(ctx).Find(dest).Error
+}
+
+func TestQueryMetrics(t *testing.T) {
+	db := openDryRunDB(t)
+	registry := NewRegistry()
+	require.NoError(t, db.Use(NewQueryMetrics(registry, testMethod)))
+
+	failing := true
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:fail", func(db *gorm.DB) {
+		if failing {
+			_ = db.AddError(errors.New("db down"))
+		}
+	}))
+
+	repo := &testRepository{db: db}
+	require.Error(t, repo.ListProducts(t.Context()))
+	failing = false
+	require.NoError(t, repo.ListProducts(t.Context()))
+	require.NoError(t, repo.UpdateProduct(t.Context()))
+
+	var products []testProduct
+	require.NoError(t, db.Find(&products).Error)
+
+	var out strings.Builder
+	_, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `db_query_duration_seconds_count{method="testRepository.ListProducts"} 2`)
+	assert.Contains(t, out.String(), `db_query_duration_seconds_count{method="testRepository.UpdateProduct"} 1`)
+	assert.Contains(t, out.String(), `db_query_duration_seconds_count{method="unknown"} 1`)
+	assert.Contains(t, out.String(), `db_query_errors_total{method="testRepository.ListProducts"} 1`)
+	assert.NotContains(t, out.String(), `db_query_errors_total{method="testRepository.UpdateProduct"}`)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/metrics"
+)
+
+// Metrics records the count and latency of requests per route pattern and status. Like AccessLog,
+// it reads the pattern matched by the http.ServeMux, so it must not replace the request on its way there.
+func Metrics(m *metrics.HTTPMetrics) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			m.Observe(r.Pattern, status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /catalog/{code}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("code") == "PANIC" {
+			panic("boom")
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := Chain(mux, Metrics(metrics.NewHTTPMetrics(registry)), Recover(slog.New(slog.DiscardHandler)))
+
+	for _, path := range []string{"/catalog/PROD001", "/catalog/PROD002", "/catalog/PANIC", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var out strings.Builder
+	_, err := registry.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `http_requests_total{route="GET /catalog/{code}",status="404"} 2`)
+	assert.Contains(t, out.String(), `http_requests_total{route="GET /catalog/{code}",status="500"} 1`)
+	assert.Contains(t, out.String(), `http_requests_total{route="unmatched",status="404"} 1`)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}), tag("outer"), tag("inner"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestResponseRecorder(t *testing.T) {
+	t.Run("implicit status", func(t *testing.T) {
+		recorder := &responseRecorder{ResponseWriter: httptest.NewRecorder()}
+
+		_, _ = recorder.Write([]byte("hello"))
+		_, _ = recorder.Write([]byte(" world"))
+
+		assert.Equal(t, http.StatusOK, recorder.status)
+		assert.Equal(t, int64(11), recorder.bytes)
+	})
+
+	t.Run("first status wins", func(t *testing.T) {
+		underlying := httptest.NewRecorder()
+		recorder := &responseRecorder{ResponseWriter: underlying}
+
+		recorder.WriteHeader(http.StatusCreated)
+		recorder.WriteHeader(http.StatusInternalServerError)
+
+		assert.Equal(t, http.StatusCreated, recorder.status)
+		assert.Equal(t, http.StatusCreated, underlying.Code)
+	})
+
+	t.Run("unwraps to the response writer", func(t *testing.T) {
+		underlying := httptest.NewRecorder()
+		recorder := &responseRecorder{ResponseWriter: underlying}
+
+		assert.NoError(t, http.NewResponseController(recorder).Flush())
+		assert.True(t, underlying.Flushed)
+	})
+}
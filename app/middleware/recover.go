@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+)
+
+// Recover turns a panicking handler into an HTTP 500 problem and logs the panic with its stack.
+// Nothing more is written when the handler already sent the response headers, and
+// http.ErrAbortHandler is re-raised so that the server aborts the response as intended.
+func Recover(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.LogAttrs(r.Context(), slog.LevelError, "panic recovered",
+					slog.String("panic", fmt.Sprint(recovered)),
+					slog.String("stack", string(debug.Stack())),
+					slog.String("request_id", requestid.FromContext(r.Context())),
+				)
+
+				if recorder.status == 0 {
+					api.ErrorResponse(recorder, r, http.StatusInternalServerError, api.CodeInternal, "internal server error")
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	t.Run("panics become http500 problems", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&out, nil))
+		handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}), RequestID, Recover(logger))
+
+		req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		req.Header.Set(requestid.Header, "req-1")
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		assert.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+
+		var problem api.Problem
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &problem))
+		assert.Equal(t, api.CodeInternal, problem.Code)
+		assert.Equal(t, "req-1", problem.RequestID)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+		assert.Equal(t, "panic recovered", entry["msg"])
+		assert.Equal(t, "boom", entry["panic"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Contains(t, entry["stack"], "recover_test.go")
+	})
+
+	t.Run("written responses are left alone", func(t *testing.T) {
+		handler := Recover(slog.New(slog.DiscardHandler))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("partial"))
+			panic("boom")
+		}))
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/catalog/export", nil))
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "partial", res.Body.String())
+	})
+
+	t.Run("aborted handlers are re-raised", func(t *testing.T) {
+		handler := Recover(slog.New(slog.DiscardHandler))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/catalog", nil))
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+)
+
+// maxRequestIDLength bounds the length of propagated request IDs.
+const maxRequestIDLength = 128
+
+// RequestID propagates the X-Request-ID header of the request, or generates an ID when it is missing
+// or malformed. The ID is echoed in the response header and carried by the request context,
+// see requestid.FromContext.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !validRequestID(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
+
+// validRequestID reports whether id is a non-empty, bounded string of visible ASCII characters,
+// so that it can be safely logged and echoed.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
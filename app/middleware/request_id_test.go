@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	cases := map[string]struct {
+		header    string
+		propagate bool
+	}{
+		"propagated":     {header: "abc-123", propagate: true},
+		"missing":        {header: ""},
+		"too long":       {header: strings.Repeat("a", maxRequestIDLength+1)},
+		"with spaces":    {header: "abc 123"},
+		"with non ascii": {header: "abcé"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+			if tc.header != "" {
+				req.Header.Set(requestid.Header, tc.header)
+			}
+			res := httptest.NewRecorder()
+
+			handler.ServeHTTP(res, req)
+
+			assert.NotEmpty(t, seen)
+			assert.Equal(t, seen, res.Header().Get(requestid.Header))
+			if tc.propagate {
+				assert.Equal(t, tc.header, seen)
+			} else {
+				assert.NotEqual(t, tc.header, seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockKey identifies the advisory lock serializing concurrent migration runs.
+const lockKey = 727274
+
+var (
+	// ErrIrreversibleMigration indicates that an applied migration has no down script.
+	ErrIrreversibleMigration = errors.New("migration has no down script")
+
+	// ErrMissingMigration indicates that an applied version has no migration file.
+	ErrMissingMigration = errors.New("applied migration file not found")
+)
+
+// migrationFilePattern matches migration files such as 0001_products.up.sql.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change. Down is empty for irreversible migrations.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// AppliedMigration is a row of the schema_migrations tracking table.
+type AppliedMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName returns the database table name for AppliedMigration.
+func (a *AppliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status reports whether a migration is applied. Missing is true for applied versions without a migration file.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Missing   bool
+}
+
+// LoadDir loads the migrations of a directory, see Load.
+func LoadDir(dir string) ([]Migration, error) {
+	return Load(os.DirFS(dir))
+}
+
+// Load reads the migrations at the root of fsys, ordered by version. Every migration needs a
+// VERSION_NAME.up.sql file and may have a VERSION_NAME.down.sql file; other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations failed: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s failed: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and rolls back migrations, recording applied versions in the schema_migrations table.
+// Every migration runs in its own transaction together with its tracking row, so a failed migration
+// leaves neither partial changes nor a record behind. Concurrent runs are serialized with an advisory lock.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New creates a migrator for migrations ordered by version.
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Status lists every known or applied migration ordered by version.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedByVersion := make(map[int64]AppliedMigration, len(applied))
+	for _, migration := range applied {
+		appliedByVersion[migration.Version] = migration
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := appliedByVersion[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &row.AppliedAt
+			delete(appliedByVersion, migration.Version)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, row := range appliedByVersion {
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: &row.AppliedAt, Missing: true})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Up applies up to steps pending migrations in version order and returns the applied migrations.
+// A steps value of zero or less applies every pending migration.
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	var done []Migration
+	for _, migration := range m.migrations {
+		if steps > 0 && len(done) == steps {
+			break
+		}
+
+		applied, err := m.run(func(tx *gorm.DB) (bool, error) {
+			if err := tx.Where("version = ?", migration.Version).Take(&AppliedMigration{}).Error; err == nil {
+				return false, nil
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, fmt.Errorf("check migration failed: %w", err)
+			}
+
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return false, fmt.Errorf("migration %d_%s up failed: %w", migration.Version, migration.Name, err)
+			}
+
+			row := AppliedMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			if err := tx.Create(&row).Error; err != nil {
+				return false, fmt.Errorf("record migration failed: %w", err)
+			}
+
+			return true, nil
+		})
+		if err != nil {
+			return done, err
+		}
+
+		if applied {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns the rolled back migrations.
+// It fails with ErrMissingMigration or ErrIrreversibleMigration when a migration cannot be rolled back.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var done []Migration
+	for len(done) < steps {
+		var migration Migration
+		rolledBack, err := m.run(func(tx *gorm.DB) (bool, error) {
+			var latest AppliedMigration
+			if err := tx.Order("version DESC").Take(&latest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return false, nil
+				}
+
+				return false, fmt.Errorf("find latest migration failed: %w", err)
+			}
+
+			var ok bool
+			if migration, ok = byVersion[latest.Version]; !ok {
+				return false, fmt.Errorf("migration %d_%s: %w", latest.Version, latest.Name, ErrMissingMigration)
+			}
+
+			if migration.Down == "" {
+				return false, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, ErrIrreversibleMigration)
+			}
+
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return false, fmt.Errorf("migration %d_%s down failed: %w", migration.Version, migration.Name, err)
+			}
+
+			if err := tx.Delete(&latest).Error; err != nil {
+				return false, fmt.Errorf("remove migration record failed: %w", err)
+			}
+
+			return true, nil
+		})
+		if err != nil {
+			return done, err
+		}
+
+		if !rolledBack {
+			break
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// run creates the tracking table and calls fn in a transaction holding the migration lock.
+func (m *Migrator) run(fn func(tx *gorm.DB) (bool, error)) (bool, error) {
+	if err := m.ensureTable(); err != nil {
+		return false, err
+	}
+
+	var changed bool
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+			return fmt.Errorf("lock migrations failed: %w", err)
+		}
+
+		var err error
+		changed, err = fn(tx)
+		return err
+	})
+
+	return changed, err
+}
+
+func (m *Migrator) applied() ([]AppliedMigration, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	var applied []AppliedMigration
+	if err := m.db.Order("version ASC").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("list applied migrations failed: %w", err)
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	if err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`).Error; err != nil {
+		return fmt.Errorf("create schema_migrations failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pairs up and down scripts ordered by version", func(t *testing.T) {
+		migrations, err := Load(fstest.MapFS{
+			"0010_stock.up.sql":      {Data: []byte("CREATE TABLE stock ();")},
+			"0002_variants.up.sql":   {Data: []byte("CREATE TABLE variants ();")},
+			"0002_variants.down.sql": {Data: []byte("DROP TABLE variants;")},
+			"0001_products.up.sql":   {Data: []byte("CREATE TABLE products ();")},
+			"README.md":              {Data: []byte("notes")},
+			"0003_notes.sql":         {Data: []byte("SELECT 1;")},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{
+			{Version: 1, Name: "products", Up: "CREATE TABLE products ();"},
+			{Version: 2, Name: "variants", Up: "CREATE TABLE variants ();", Down: "DROP TABLE variants;"},
+			{Version: 10, Name: "stock", Up: "CREATE TABLE stock ();"},
+		}, migrations)
+	})
+
+	t.Run("rejects a down script without up script", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{"0001_products.down.sql": {Data: []byte("DROP TABLE products;")}})
+		assert.ErrorContains(t, err, "has no up script")
+	})
+
+	t.Run("rejects conflicting names", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{
+			"0001_products.up.sql": {Data: []byte("CREATE TABLE products ();")},
+			"0001_items.down.sql":  {Data: []byte("DROP TABLE items;")},
+		})
+		assert.ErrorContains(t, err, "conflicting names")
+	})
+
+	t.Run("loads the repository migrations", func(t *testing.T) {
+		migrations, err := LoadDir("../../sql/migrations")
+
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+		for i, migration := range migrations {
+			assert.Equal(t, int64(i+1), migration.Version)
+			assert.NotEmpty(t, migration.Down, migration.Name)
+		}
+	})
+}
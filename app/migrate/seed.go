@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Seed drops every table of the public schema, applies all migrations and loads the fixtures of an sql
+// directory holding reset.sql, a migrations directory and a fixtures directory. It destroys all data and
+// is only meant for local and test databases.
+func Seed(db *gorm.DB, dir string) error {
+	reset, err := os.ReadFile(filepath.Join(dir, "reset.sql"))
+	if err != nil {
+		return fmt.Errorf("read reset script failed: %w", err)
+	}
+
+	if err := db.Exec(string(reset)).Error; err != nil {
+		return fmt.Errorf("reset database failed: %w", err)
+	}
+
+	migrations, err := LoadDir(filepath.Join(dir, "migrations"))
+	if err != nil {
+		return err
+	}
+
+	if _, err := New(db, migrations).Up(0); err != nil {
+		return err
+	}
+
+	_, err = ExecDir(db, filepath.Join(dir, "fixtures"))
+	return err
+}
+
+// ExecDir executes the .sql files of a directory in name order, each in its own transaction,
+// and returns the names of the executed files. It stops at the first failing file.
+func ExecDir(db *gorm.DB, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read directory failed: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return names[:i], fmt.Errorf("read %s failed: %w", name, err)
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.Exec(string(content)).Error
+		}); err != nil {
+			return names[:i], fmt.Errorf("execute %s failed: %w", name, err)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/importer"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+func main() {
+	file := flag.String("file", "", "CSV or NDJSON file to import, - reads standard input")
+	formatName := flag.String("format", "", "file format, csv or ndjson (default: taken from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "validate the file without persisting any change")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("missing -file")
+	}
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	format, ok := importer.ParseFormat(*formatName)
+	if !ok {
+		log.Fatalf("unsupported format %q, use -format csv or -format ndjson", *formatName)
+	}
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	var src io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("opening file failed: %v", err)
+		}
+		defer f.Close()
+		src = f
+	}
+
+	// Initialize database connection
+	cfg, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("invalid database configuration: %s", err)
+	}
+
+	db, close, err := database.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer close()
+
+	report, err := importer.New(models.NewImportRepository(db)).Import(context.Background(), src, format, *dryRun)
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("writing report failed: %v", err)
+	}
+
+	if len(report.Errors) > 0 {
+		log.Printf("import rejected: %d invalid rows", len(report.Errors))
+		close()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/migrate"
+)
+
+const usage = `usage: migrate [-dir DIR] COMMAND
+
+Commands:
+  up [N]     apply the next N pending migrations, all of them when N is omitted
+  down [N]   roll back the last N applied migrations, one when N is omitted
+  status     list migrations and whether they are applied
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	dir := flag.String("dir", "", "migrations directory (default: $POSTGRES_SQL_DIR/migrations)")
+	flag.Parse()
+
+	command, steps := flag.Arg(0), 0
+	if flag.NArg() > 2 || (command != "up" && command != "down" && command != "status") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 2 {
+		n, err := strconv.Atoi(flag.Arg(1))
+		if err != nil || n <= 0 || command == "status" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		steps = n
+	}
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	if *dir == "" {
+		*dir = filepath.Join(os.Getenv("POSTGRES_SQL_DIR"), "migrations")
+	}
+
+	migrations, err := migrate.LoadDir(*dir)
+	if err != nil {
+		log.Fatalf("loading migrations failed: %v", err)
+	}
+
+	// Initialize database connection
+	cfg, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("invalid database configuration: %s", err)
+	}
+
+	db, close, err := database.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer close()
+
+	migrator := migrate.New(db, migrations)
+	switch command {
+	case "up":
+		applied, err := migrator.Up(steps)
+		for _, migration := range applied {
+			log.Printf("Applied %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrating up failed: %v", err)
+		}
+
+		if len(applied) == 0 {
+			log.Println("No pending migrations")
+		}
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+
+		rolledBack, err := migrator.Down(steps)
+		for _, migration := range rolledBack {
+			log.Printf("Rolled back %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrating down failed: %v", err)
+		}
+
+		if len(rolledBack) == 0 {
+			log.Println("No applied migrations")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("reading migration status failed: %v", err)
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Missing {
+				state += " (file missing)"
+			}
+
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	}
+}
@@ -3,13 +3,11 @@ package main
 import (
 	"log"
 	"os"
-	"path/filepath"
-	"sort"
-	"strings"
 
 	"github.com/joho/godotenv"
 
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/migrate"
 )
 
 func main() {
@@ -19,45 +17,22 @@ func main() {
 	}
 
 	// Initialize database connection
-	db, close := database.New(
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-	)
-	defer close()
-
-	dir := os.Getenv("POSTGRES_SQL_DIR")
-	files, err := os.ReadDir(dir)
+	cfg, err := database.ConfigFromEnv()
 	if err != nil {
-		log.Fatalf("reading directory failed: %v", err)
+		log.Fatalf("invalid database configuration: %s", err)
 	}
 
-	// Filter and sort .sql files
-	var sqlFiles []os.DirEntry
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file)
-		}
+	db, close, err := database.New(cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
-	sort.Slice(sqlFiles, func(i, j int) bool {
-		return sqlFiles[i].Name() < sqlFiles[j].Name()
-	})
-
-	for _, file := range sqlFiles {
-		path := filepath.Join(dir, file.Name())
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("reading file %s failed: %v", file.Name(), err)
-		}
-
-		sql := string(content)
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("executing %s failed: %v", file.Name(), err)
-			return
-		}
+	defer close()
 
-		log.Printf("Executed %s successfully\n", file.Name())
+	// Drop every table, apply all migrations and load the demo fixtures
+	dir := os.Getenv("POSTGRES_SQL_DIR")
+	if err := migrate.Seed(db, dir); err != nil {
+		log.Fatalf("seeding failed: %v", err)
 	}
+
+	log.Printf("Seeded database from %s successfully\n", dir)
 }
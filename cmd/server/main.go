@@ -1,57 +1,184 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/app/catalog"
 	"github.com/mytheresa/go-hiring-challenge/app/categories"
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/exchangerates"
+	"github.com/mytheresa/go-hiring-challenge/app/importer"
+	"github.com/mytheresa/go-hiring-challenge/app/metrics"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
 	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
 )
 
+// defaultReservationSweepInterval is how often expired stock reservations are released
+// unless RESERVATION_SWEEP_INTERVAL is set.
+const defaultReservationSweepInterval = time.Minute
+
+// shutdownTimeout bounds how long in-flight requests may take to complete on shutdown.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// Load environment variables from .env file
 	if err := godotenv.Load(".env"); err != nil {
 		log.Fatalf("Error loading .env file: %s", err)
 	}
 
+	// Write structured logs, including the standard logger's output
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
 	// signal handling for graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Catalog listings validate their query parameters strictly when enabled
+	strictQuery, err := strconv.ParseBool(cmp.Or(os.Getenv("CATALOG_STRICT_QUERY"), "false"))
+	if err != nil {
+		log.Fatalf("invalid CATALOG_STRICT_QUERY: %s", err)
+	}
+
+	// Expired stock reservations are released every sweep interval
+	sweepInterval := defaultReservationSweepInterval
+	if raw := os.Getenv("RESERVATION_SWEEP_INTERVAL"); raw != "" {
+		sweepInterval, err = time.ParseDuration(raw)
+		if err != nil || sweepInterval <= 0 {
+			log.Fatalf("invalid RESERVATION_SWEEP_INTERVAL %q", raw)
+		}
+	}
+
 	// Initialize database connection
-	db, close := database.New(
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-	)
+	cfg, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("invalid database configuration: %s", err)
+	}
+
+	db, close, err := database.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 	defer close()
 
+	replicas, closeReplicas, err := database.NewReplicas(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeReplicas()
+
+	// Record the duration and errors of the repositories' queries and the connection pool statistics
+	registry := metrics.NewRegistry()
+	queryMetrics := metrics.NewQueryMetrics(registry, models.QueryMethod)
+	poolStats := metrics.NewDBStats(registry)
+	for i, conn := range append([]*gorm.DB{db}, replicas...) {
+		if err := conn.Use(queryMetrics); err != nil {
+			log.Fatalf("failed to instrument database: %s", err)
+		}
+
+		sqlDB, err := conn.DB()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		pool := "primary"
+		if i > 0 {
+			pool = fmt.Sprintf("replica%d", i)
+		}
+		poolStats.Add(pool, sqlDB)
+	}
+
+	// Route catalog reads to the read replicas
+	reads, err := models.NewReadRouter(db, replicas, cfg.PrimaryReadWindow)
+	if err != nil {
+		log.Fatalf("failed to route reads: %s", err)
+	}
+
 	// Initialize handlers
-	prodRepo := models.NewProductsRepository(db)
-	catRepo := models.NewCategoriesRepository(db)
-	cat := catalog.NewCatalogHandler(prodRepo)
+	prodRepo := models.NewReplicatedProductsRepository(db, reads)
+	catRepo := models.NewReplicatedCategoriesRepository(db, reads)
+	variantRepo := models.NewVariantsRepository(db)
+	rateRepo := models.NewExchangeRatesRepository(db)
+	priceRepo := models.NewPricesRepository(db)
+	stockRepo := models.NewStockRepository(db)
+	reservationRepo := models.NewReservationsRepository(db)
+	importRepo := models.NewImportRepository(db)
+	cat := catalog.NewCatalogHandler(prodRepo, rateRepo, priceRepo)
+	cat.SetStrictQuery(strictQuery)
+	export := catalog.NewExportHandler(prodRepo, logger)
+	variants := catalog.NewVariantsHandler(variantRepo)
+	stock := catalog.NewStockHandler(stockRepo)
+	reservations := catalog.NewReservationsHandler(reservationRepo)
 	categoriesHandler := categories.NewHandler(catRepo)
+	ratesHandler := exchangerates.NewHandler(rateRepo)
+	importHandler := importer.NewHandler(importer.New(importRepo))
 
-	// Set up routing
+	// Set up routing. The bulk import and export streams are not bound by the query timeout.
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /catalog", cat.HandleGet)
-	mux.HandleFunc("GET /catalog/{code}", cat.HandleGetByCode)
-	mux.HandleFunc("GET /categories", categoriesHandler.HandleGet)
-	mux.HandleFunc("POST /categories", categoriesHandler.HandlePost)
+	query := func(handler http.HandlerFunc) http.HandlerFunc {
+		return api.WithQueryTimeout(cfg.QueryTimeout, handler)
+	}
 
-	// Set up the HTTP server
+	mux.Handle("GET /metrics", registry.Handler())
+	mux.HandleFunc("GET /catalog", query(cat.HandleGet))
+	mux.HandleFunc("POST /catalog", query(cat.HandlePost))
+	mux.HandleFunc("GET /catalog/search", query(cat.HandleSearch))
+	mux.HandleFunc("GET /catalog/export", export.HandleGet)
+	mux.HandleFunc("POST /catalog/import", importHandler.HandlePost)
+	mux.HandleFunc("GET /catalog/{code}", query(cat.HandleGetByCode))
+	mux.HandleFunc("PUT /catalog/{code}", query(cat.HandlePut))
+	mux.HandleFunc("PATCH /catalog/{code}", query(cat.HandlePatch))
+	mux.HandleFunc("DELETE /catalog/{code}", query(cat.HandleDelete))
+	mux.HandleFunc("GET /catalog/{code}/variants", query(variants.HandleList))
+	mux.HandleFunc("POST /catalog/{code}/variants", query(variants.HandlePost))
+	mux.HandleFunc("PUT /catalog/{code}/variants/{sku}", query(variants.HandlePut))
+	mux.HandleFunc("PATCH /catalog/{code}/variants/{sku}", query(variants.HandlePatch))
+	mux.HandleFunc("DELETE /catalog/{code}/variants/{sku}", query(variants.HandleDelete))
+	mux.HandleFunc("GET /catalog/{code}/variants/{sku}/stock", query(stock.HandleGet))
+	mux.HandleFunc("POST /catalog/{code}/variants/{sku}/stock/adjustments", query(stock.HandleAdjust))
+	mux.HandleFunc("PUT /catalog/{code}/variants/{sku}/stock/{warehouse}", query(stock.HandlePut))
+	mux.HandleFunc("POST /reservations", query(reservations.HandlePost))
+	mux.HandleFunc("GET /reservations/{id}", query(reservations.HandleGet))
+	mux.HandleFunc("POST /reservations/{id}/commit", query(reservations.HandleCommit))
+	mux.HandleFunc("POST /reservations/{id}/release", query(reservations.HandleRelease))
+	mux.HandleFunc("GET /categories", query(categoriesHandler.HandleGet))
+	mux.HandleFunc("POST /categories", query(categoriesHandler.HandlePost))
+	mux.HandleFunc("GET /categories/tree", query(categoriesHandler.HandleGetTree))
+	mux.HandleFunc("GET /categories/{code}", query(categoriesHandler.HandleGetByCode))
+	mux.HandleFunc("PUT /categories/{code}", query(categoriesHandler.HandlePut))
+	mux.HandleFunc("DELETE /categories/{code}", query(categoriesHandler.HandleDelete))
+	mux.HandleFunc("GET /exchange-rates", query(ratesHandler.HandleGet))
+	mux.HandleFunc("PUT /exchange-rates/{base}/{quote}", query(ratesHandler.HandlePut))
+	mux.HandleFunc("DELETE /exchange-rates/{base}/{quote}", query(ratesHandler.HandleDelete))
+
+	// Tag requests with an ID, log and measure them, and recover their panics into HTTP 500 problems
+	handler := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.AccessLog(logger),
+		middleware.Metrics(metrics.NewHTTPMetrics(registry)),
+		middleware.Recover(logger),
+	)
+
+	// Set up the HTTP server. Requests derive their context from ctx, so a shutdown cancels their queries
 	srv := &http.Server{
-		Addr:    fmt.Sprintf("localhost:%s", os.Getenv("HTTP_PORT")),
-		Handler: mux,
+		Addr:        fmt.Sprintf("localhost:%s", os.Getenv("HTTP_PORT")),
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
 	// Start the server
@@ -64,8 +191,22 @@ func main() {
 		log.Println("Server stopped gracefully")
 	}()
 
+	// Release expired stock reservations in the background
+	var sweeper sync.WaitGroup
+	sweeper.Add(1)
+	go func() {
+		defer sweeper.Done()
+		catalog.NewReservationSweeper(reservationRepo, sweepInterval, logger).Run(ctx)
+		log.Println("Reservation sweeper stopped")
+	}()
+
 	<-ctx.Done()
 	log.Println("Shutting down server...")
-	srv.Shutdown(ctx)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %s", err)
+	}
+	sweeper.Wait()
 	stop()
 }
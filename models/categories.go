@@ -1,10 +1,13 @@
 package models
 
 // Category represents a product category.
+// Root categories have no parent.
 type Category struct {
-	ID   uint   `gorm:"primaryKey"`
-	Code string `gorm:"uniqueIndex;not null"`
-	Name string `gorm:"not null"`
+	ID       uint      `gorm:"primaryKey"`
+	Code     string    `gorm:"uniqueIndex;not null"`
+	Name     string    `gorm:"not null"`
+	ParentID *uint     `gorm:"index"`
+	Parent   *Category `gorm:"foreignKey:ParentID"`
 }
 
 // TableName returns the database table name for Category.
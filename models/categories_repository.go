@@ -1,19 +1,72 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
 
-	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// ErrCategoryCodeAlreadyExists indicates a unique violation for category code.
-var ErrCategoryCodeAlreadyExists = errors.New("category code already exists")
+var (
+	// ErrCategoryCodeAlreadyExists indicates a unique violation for category code.
+	ErrCategoryCodeAlreadyExists = errors.New("category code already exists")
+
+	// ErrCategoryNotFound indicates that no category matches the given code.
+	ErrCategoryNotFound = errors.New("category not found")
+
+	// ErrCategoryInUse indicates that a category still has products assigned.
+	ErrCategoryInUse = errors.New("category in use")
+
+	// ErrReassignCategoryNotFound indicates that the category products should move to does not exist.
+	ErrReassignCategoryNotFound = errors.New("reassignment category not found")
+
+	// ErrParentCategoryNotFound indicates that the requested parent category does not exist.
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+
+	// ErrCategoryCycle indicates that a category would become its own ancestor.
+	ErrCategoryCycle = errors.New("category cannot be its own ancestor")
+
+	// ErrCategoryHasChildren indicates that a category still has subcategories.
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+// CategoryInUseError reports how many products still reference a category.
+// ProductCount is zero when the count is unknown, as when a product is added while the category is deleted.
+// It matches ErrCategoryInUse with errors.Is.
+type CategoryInUseError struct {
+	ProductCount int64
+}
+
+// Error implements the error interface.
+func (e *CategoryInUseError) Error() string {
+	if e.ProductCount == 0 {
+		return ErrCategoryInUse.Error()
+	}
+
+	return fmt.Sprintf("category in use by %d products", e.ProductCount)
+}
+
+// Is reports whether target is ErrCategoryInUse.
+func (e *CategoryInUseError) Is(target error) bool {
+	return target == ErrCategoryInUse
+}
 
 // CategoriesRepository provides persistence operations for categories.
+// Category listings go through reads when it is set and through db otherwise.
 type CategoriesRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	reads *ReadRouter
+}
+
+// CategoryInput defines the writable fields of a category.
+// An empty ParentCode makes the category a root category.
+type CategoryInput struct {
+	Code       string
+	Name       string
+	ParentCode string
 }
 
 // NewCategoriesRepository creates a categories repository backed by gorm.
@@ -21,26 +74,236 @@ func NewCategoriesRepository(db *gorm.DB) *CategoriesRepository {
 	return &CategoriesRepository{db: db}
 }
 
-// GetAllCategories returns all categories ordered by id.
-func (r *CategoriesRepository) GetAllCategories() ([]Category, error) {
+// NewReplicatedCategoriesRepository creates a categories repository writing to db and routing category listings through reads.
+func NewReplicatedCategoriesRepository(db *gorm.DB, reads *ReadRouter) *CategoriesRepository {
+	return &CategoriesRepository{db: db, reads: reads}
+}
+
+// reader returns the connection used by category listings, bound to ctx.
+func (r *CategoriesRepository) reader(ctx context.Context) *gorm.DB {
+	if r.reads == nil {
+		return r.db.WithContext(ctx)
+	}
+
+	return r.reads.Reader().WithContext(ctx)
+}
+
+// GetAllCategories returns all categories ordered by id with their parent preloaded.
+func (r *CategoriesRepository) GetAllCategories(ctx context.Context) ([]Category, error) {
+	ctx = withQueryMethod(ctx, "CategoriesRepository.GetAllCategories")
+
 	var categories []Category
-	if err := r.db.Order("id ASC").Find(&categories).Error; err != nil {
+	if err := r.reader(ctx).Preload("Parent").Order("id ASC").Find(&categories).Error; err != nil {
 		return nil, fmt.Errorf("list categories failed: %w", err)
 	}
 
 	return categories, nil
 }
 
-// CreateCategory persists a new category.
-func (r *CategoriesRepository) CreateCategory(category Category) (*Category, error) {
-	if err := r.db.Create(&category).Error; err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, ErrCategoryCodeAlreadyExists
+// CreateCategory persists a new category under the optional parent category.
+func (r *CategoriesRepository) CreateCategory(ctx context.Context, input CategoryInput) (*Category, error) {
+	ctx = withQueryMethod(ctx, "CategoriesRepository.CreateCategory")
+
+	var category Category
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parentID, err := findParentCategoryID(tx, input.ParentCode)
+		if err != nil {
+			return err
+		}
+
+		category = Category{Code: input.Code, Name: input.Name, ParentID: parentID}
+		if err := tx.Omit(clause.Associations).Create(&category).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrCategoryCodeAlreadyExists
+			}
+
+			return fmt.Errorf("create category failed: %w", err)
+		}
+
+		return loadCategory(tx, &category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// GetCategoryByCode returns a single category by code with its parent preloaded.
+func (r *CategoriesRepository) GetCategoryByCode(ctx context.Context, code string) (*Category, error) {
+	ctx = withQueryMethod(ctx, "CategoriesRepository.GetCategoryByCode")
+
+	db := r.db.WithContext(ctx)
+	category, err := findCategoryByCode(db, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadCategory(db, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// UpdateCategory replaces the code, name and parent of the category with the given code.
+// Moving a category below itself or one of its descendants is rejected with ErrCategoryCycle.
+func (r *CategoriesRepository) UpdateCategory(ctx context.Context, code string, input CategoryInput) (*Category, error) {
+	ctx = withQueryMethod(ctx, "CategoriesRepository.UpdateCategory")
+
+	var category *Category
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := findCategoryByCode(tx, code)
+		if err != nil {
+			return err
+		}
+
+		parentID, err := findParentCategoryID(tx, input.ParentCode)
+		if err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			var subtree []uint
+			if err := categorySubtreeIDs(tx, "id = ?", existing.ID).Scan(&subtree).Error; err != nil {
+				return fmt.Errorf("load category subtree failed: %w", err)
+			}
+
+			if slices.Contains(subtree, *parentID) {
+				return ErrCategoryCycle
+			}
+		}
+
+		changes := map[string]any{"code": input.Code, "name": input.Name, "parent_id": parentID}
+		if err := tx.Model(existing).Updates(changes).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrCategoryCodeAlreadyExists
+			}
+
+			return fmt.Errorf("update category failed: %w", err)
+		}
+
+		category = existing
+		return loadCategory(tx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory removes the category with the given code.
+// When reassignTo is not empty, products of the category are moved to that category first.
+// Otherwise a category that still has products is rejected with a CategoryInUseError.
+// A category with subcategories is rejected with ErrCategoryHasChildren.
+func (r *CategoriesRepository) DeleteCategory(ctx context.Context, code, reassignTo string) error {
+	ctx = withQueryMethod(ctx, "CategoriesRepository.DeleteCategory")
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+
+			return fmt.Errorf("delete category failed: %w", err)
+		}
+
+		var children int64
+		if err := tx.Model(&Category{}).Where("parent_id = ?", category.ID).Count(&children).Error; err != nil {
+			return fmt.Errorf("count subcategories failed: %w", err)
+		}
+
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+
+		if reassignTo != "" {
+			target, err := findCategoryByCode(tx, reassignTo)
+			if err != nil {
+				if errors.Is(err, ErrCategoryNotFound) {
+					return ErrReassignCategoryNotFound
+				}
+
+				return err
+			}
+
+			if err := tx.Model(&Product{}).Where("category_id = ?", category.ID).Update("category_id", target.ID).Error; err != nil {
+				return fmt.Errorf("reassign products failed: %w", err)
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&Product{}).Where("category_id = ?", category.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("count category products failed: %w", err)
+		}
+
+		if count > 0 {
+			return &CategoryInUseError{ProductCount: count}
+		}
+
+		if err := tx.Delete(&category).Error; err != nil {
+			// A product was added concurrently. The aborted transaction cannot count it.
+			if isForeignKeyViolation(err) {
+				return &CategoryInUseError{}
+			}
+
+			return fmt.Errorf("delete category failed: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// findCategoryByCode looks up a category by its exact code.
+func findCategoryByCode(db *gorm.DB, code string) (*Category, error) {
+	var category Category
+	if err := db.Where("code = ?", code).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
 		}
 
-		return nil, fmt.Errorf("create category failed: %w", err)
+		return nil, fmt.Errorf("find category failed: %w", err)
 	}
 
 	return &category, nil
 }
+
+// findParentCategoryID resolves an optional parent category code to its id.
+// An empty code resolves to nil.
+func findParentCategoryID(db *gorm.DB, parentCode string) (*uint, error) {
+	if parentCode == "" {
+		return nil, nil
+	}
+
+	parent, err := findCategoryByCode(db, parentCode)
+	if err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return nil, ErrParentCategoryNotFound
+		}
+
+		return nil, err
+	}
+
+	return &parent.ID, nil
+}
+
+// loadCategory reloads a category with its parent preloaded.
+func loadCategory(db *gorm.DB, category *Category) error {
+	if err := db.Preload("Parent").First(category, category.ID).Error; err != nil {
+		return fmt.Errorf("load category failed: %w", err)
+	}
+
+	return nil
+}
+
+// categorySubtreeIDs builds a query selecting the ids of the categories matching condition
+// and of all their descendants. It can be scanned directly or used as a subquery.
+func categorySubtreeIDs(db *gorm.DB, condition string, args ...any) *gorm.DB {
+	return db.Raw(`WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE `+condition+`
+	UNION
+	SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+) SELECT id FROM subtree`, args...)
+}
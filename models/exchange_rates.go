@@ -0,0 +1,33 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the currency of products created without an explicit currency.
+const DefaultCurrency = "EUR"
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ExchangeRate converts amounts in BaseCurrency into QuoteCurrency by multiplying with Rate.
+type ExchangeRate struct {
+	BaseCurrency  string          `gorm:"primaryKey;size:3"`
+	QuoteCurrency string          `gorm:"primaryKey;size:3"`
+	Rate          decimal.Decimal `gorm:"type:decimal(18,8);not null"`
+	UpdatedAt     time.Time
+}
+
+// TableName returns the database table name for ExchangeRate.
+func (e *ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// NormalizeCurrency upper-cases a currency code and reports whether it is a valid ISO 4217 style code.
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, currencyCodePattern.MatchString(code)
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrExchangeRateNotFound indicates that no rate is stored for a currency pair.
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+	// ErrUnsupportedConversion indicates that no rate converts between the given currencies.
+	// It matches ErrExchangeRateNotFound with errors.Is.
+	ErrUnsupportedConversion = fmt.Errorf("unsupported currency conversion: %w", ErrExchangeRateNotFound)
+)
+
+// ExchangeRatesRepository provides persistence operations for exchange rates.
+type ExchangeRatesRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRatesRepository creates an exchange rates repository backed by gorm.
+func NewExchangeRatesRepository(db *gorm.DB) *ExchangeRatesRepository {
+	return &ExchangeRatesRepository{db: db}
+}
+
+// ListExchangeRates returns all stored exchange rates ordered by currency pair.
+func (r *ExchangeRatesRepository) ListExchangeRates(ctx context.Context) ([]ExchangeRate, error) {
+	ctx = withQueryMethod(ctx, "ExchangeRatesRepository.ListExchangeRates")
+
+	var rates []ExchangeRate
+	if err := r.db.WithContext(ctx).Order("base_currency ASC, quote_currency ASC").Find(&rates).Error; err != nil {
+		return nil, fmt.Errorf("list exchange rates failed: %w", err)
+	}
+
+	return rates, nil
+}
+
+// GetExchangeRate returns the rate converting amounts from one currency into another.
+// Converting a currency into itself yields 1, and a missing direct rate falls back to
+// the inverse of the opposite rate.
+func (r *ExchangeRatesRepository) GetExchangeRate(ctx context.Context, from, to string) (decimal.Decimal, error) {
+	ctx = withQueryMethod(ctx, "ExchangeRatesRepository.GetExchangeRate")
+
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	var rates []ExchangeRate
+	if err := r.db.WithContext(ctx).
+		Where("(base_currency = ? AND quote_currency = ?) OR (base_currency = ? AND quote_currency = ?)", from, to, to, from).
+		Find(&rates).Error; err != nil {
+		return decimal.Decimal{}, fmt.Errorf("get exchange rate failed: %w", err)
+	}
+
+	for _, rate := range rates {
+		if rate.BaseCurrency == from {
+			return rate.Rate, nil
+		}
+	}
+
+	for _, rate := range rates {
+		if rate.BaseCurrency == to && !rate.Rate.IsZero() {
+			return decimal.NewFromInt(1).Div(rate.Rate), nil
+		}
+	}
+
+	return decimal.Decimal{}, ErrUnsupportedConversion
+}
+
+// UpsertExchangeRate creates or replaces the rate for a currency pair.
+func (r *ExchangeRatesRepository) UpsertExchangeRate(ctx context.Context, rate ExchangeRate) (*ExchangeRate, error) {
+	ctx = withQueryMethod(ctx, "ExchangeRatesRepository.UpsertExchangeRate")
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&rate).Error; err != nil {
+		return nil, fmt.Errorf("upsert exchange rate failed: %w", err)
+	}
+
+	return &rate, nil
+}
+
+// DeleteExchangeRate removes the rate for a currency pair.
+func (r *ExchangeRatesRepository) DeleteExchangeRate(ctx context.Context, base, quote string) error {
+	ctx = withQueryMethod(ctx, "ExchangeRatesRepository.DeleteExchangeRate")
+
+	result := r.db.WithContext(ctx).Where("base_currency = ? AND quote_currency = ?", base, quote).Delete(&ExchangeRate{})
+	if result.Error != nil {
+		return fmt.Errorf("delete exchange rate failed: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrExchangeRateNotFound
+	}
+
+	return nil
+}
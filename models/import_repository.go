@@ -0,0 +1,220 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrVariantSKUOfAnotherProduct indicates that an imported SKU already belongs to a different product.
+var ErrVariantSKUOfAnotherProduct = errors.New("variant sku belongs to another product")
+
+// errImportRolledBack rolls back an import transaction without reporting a failure.
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportRowKind is the kind of entity an import row upserts.
+type ImportRowKind string
+
+const (
+	// ImportCategory rows upsert a category by code.
+	ImportCategory ImportRowKind = "category"
+	// ImportProduct rows upsert a product by code.
+	ImportProduct ImportRowKind = "product"
+	// ImportVariant rows upsert a variant by SKU.
+	ImportVariant ImportRowKind = "variant"
+)
+
+// ImportRow is a validated import row. Only the input matching Kind is used.
+// Line is the position of the row in the source file and is used in error reports.
+type ImportRow struct {
+	Line        int
+	Kind        ImportRowKind
+	Category    CategoryInput
+	Product     ProductInput
+	ProductCode string
+	Variant     VariantInput
+}
+
+// ImportRowError reports why a row could not be imported.
+type ImportRowError struct {
+	Line    int
+	Message string
+}
+
+// ImportResult summarizes an import. Committed is false when the import was a dry run
+// or when any row failed, in which case no change was persisted.
+type ImportResult struct {
+	Created   int
+	Updated   int
+	Errors    []ImportRowError
+	Committed bool
+}
+
+// ImportRepository upserts categories, products and variants in bulk.
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+// NewImportRepository creates an import repository backed by gorm.
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// Import upserts the rows in a single transaction: categories first, then products, then variants,
+// each in row order, so rows may reference categories and products defined earlier in the import.
+// Every row runs in its own savepoint so that all row errors are reported. The transaction is only
+// committed when no row failed and dryRun is false. Errors other than row errors abort the import.
+func (r *ImportRepository) Import(ctx context.Context, rows []ImportRow, dryRun bool) (*ImportResult, error) {
+	ctx = withQueryMethod(ctx, "ImportRepository.Import")
+
+	result := &ImportResult{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, kind := range []ImportRowKind{ImportCategory, ImportProduct, ImportVariant} {
+			for _, row := range rows {
+				if row.Kind != kind {
+					continue
+				}
+
+				var created bool
+				err := tx.Transaction(func(rowTx *gorm.DB) error {
+					var err error
+					created, err = importRow(ctx, rowTx, row)
+					return err
+				})
+				if err != nil {
+					message, ok := importRowErrorMessage(err)
+					if !ok {
+						return fmt.Errorf("import line %d failed: %w", row.Line, err)
+					}
+
+					result.Errors = append(result.Errors, ImportRowError{Line: row.Line, Message: message})
+					continue
+				}
+
+				if created {
+					result.Created++
+				} else {
+					result.Updated++
+				}
+			}
+		}
+
+		if dryRun || len(result.Errors) > 0 {
+			return errImportRolledBack
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+
+	result.Committed = err == nil
+	return result, nil
+}
+
+// importRow upserts a single row and reports whether it created a new entity.
+func importRow(ctx context.Context, tx *gorm.DB, row ImportRow) (bool, error) {
+	switch row.Kind {
+	case ImportCategory:
+		return importCategory(ctx, tx, row.Category)
+	case ImportProduct:
+		return importProduct(ctx, tx, row.Product)
+	case ImportVariant:
+		return importVariant(ctx, tx, row.ProductCode, row.Variant)
+	default:
+		return false, fmt.Errorf("unknown import row kind %q", row.Kind)
+	}
+}
+
+func importCategory(ctx context.Context, tx *gorm.DB, input CategoryInput) (bool, error) {
+	categories := NewCategoriesRepository(tx)
+	if _, err := findCategoryByCode(tx, input.Code); err != nil {
+		if !errors.Is(err, ErrCategoryNotFound) {
+			return false, err
+		}
+
+		_, err := categories.CreateCategory(ctx, input)
+		return true, err
+	}
+
+	_, err := categories.UpdateCategory(ctx, input.Code, input)
+	return false, err
+}
+
+func importProduct(ctx context.Context, tx *gorm.DB, input ProductInput) (bool, error) {
+	products := NewProductsRepository(tx)
+	if _, err := findProductByCode(tx, input.Code); err != nil {
+		if !errors.Is(err, ErrProductNotFound) {
+			return false, err
+		}
+
+		_, err := products.CreateProduct(ctx, input)
+		return true, err
+	}
+
+	update := ProductUpdate{
+		Name:         &input.Name,
+		Description:  &input.Description,
+		Price:        &input.Price,
+		CategoryCode: &input.CategoryCode,
+	}
+	if input.Currency != "" {
+		update.Currency = &input.Currency
+	}
+
+	_, err := products.UpdateProduct(ctx, input.Code, update)
+	return false, err
+}
+
+func importVariant(ctx context.Context, tx *gorm.DB, productCode string, input VariantInput) (bool, error) {
+	variants := NewVariantsRepository(tx)
+
+	var existing Variant
+	if err := tx.Where("sku = ?", input.SKU).First(&existing).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, fmt.Errorf("find variant failed: %w", err)
+		}
+
+		_, err := variants.CreateVariant(ctx, productCode, input)
+		return true, err
+	}
+
+	if _, err := findProductVariant(tx, productCode, input.SKU); err != nil {
+		if errors.Is(err, ErrVariantNotFound) {
+			return false, ErrVariantSKUOfAnotherProduct
+		}
+
+		return false, err
+	}
+
+	_, err := variants.UpdateVariant(ctx, productCode, input.SKU, VariantUpdate{Name: &input.Name, SetPrice: true, Price: input.Price})
+	return false, err
+}
+
+// importRowErrors are the errors caused by the content of a row rather than by the database.
+var importRowErrors = []error{
+	ErrCategoryCodeAlreadyExists,
+	ErrCategoryNotFound,
+	ErrParentCategoryNotFound,
+	ErrCategoryCycle,
+	ErrProductCodeAlreadyExists,
+	ErrProductNotFound,
+	ErrVariantSKUAlreadyExists,
+	ErrVariantNotFound,
+	ErrVariantSKUOfAnotherProduct,
+}
+
+// importRowErrorMessage returns the message reported for a row error.
+// It reports false for errors that are not caused by the row.
+func importRowErrorMessage(err error) (string, bool) {
+	for _, target := range importRowErrors {
+		if errors.Is(err, target) {
+			return target.Error(), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// isPgError reports whether err wraps a postgres error with the given SQLSTATE code.
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+func isUniqueViolation(err error) bool {
+	return isPgError(err, pgUniqueViolation)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return isPgError(err, pgForeignKeyViolation)
+}
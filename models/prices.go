@@ -0,0 +1,216 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Market is a sales region with its own price list currency.
+type Market struct {
+	Code     string `gorm:"primaryKey;size:2"`
+	Name     string `gorm:"not null"`
+	Currency string `gorm:"type:char(3);not null"`
+}
+
+// TableName returns the database table name for Market.
+func (m *Market) TableName() string {
+	return "markets"
+}
+
+// MarketPrice is the list price of a product, or of one of its variants, in a market.
+// A product is listed in a market when it has a market price without variant.
+type MarketPrice struct {
+	ID         uint            `gorm:"primaryKey"`
+	ProductID  uint            `gorm:"not null"`
+	VariantID  *uint           `gorm:"index"`
+	MarketCode string          `gorm:"size:2;not null"`
+	Price      decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+}
+
+// TableName returns the database table name for MarketPrice.
+func (p *MarketPrice) TableName() string {
+	return "market_prices"
+}
+
+// SalePrice is a discounted price valid from StartsAt until EndsAt.
+// A sale with a market code applies to that market's price list, one without to the stored prices.
+type SalePrice struct {
+	ID         uint            `gorm:"primaryKey"`
+	ProductID  uint            `gorm:"not null"`
+	VariantID  *uint           `gorm:"index"`
+	MarketCode *string         `gorm:"size:2"`
+	Price      decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	StartsAt   time.Time       `gorm:"not null"`
+	EndsAt     time.Time       `gorm:"not null"`
+}
+
+// TableName returns the database table name for SalePrice.
+func (p *SalePrice) TableName() string {
+	return "sale_prices"
+}
+
+// ResolvedPrice is the price of a product or variant at a point in time.
+// Sale is only set when an active sale undercuts the original price.
+type ResolvedPrice struct {
+	Original   decimal.Decimal
+	Sale       *decimal.Decimal
+	SaleEndsAt *time.Time
+}
+
+// Active returns the price to pay: the sale price when there is one, the original price otherwise.
+func (p ResolvedPrice) Active() decimal.Decimal {
+	if p.Sale != nil {
+		return *p.Sale
+	}
+
+	return p.Original
+}
+
+// ProductPricing holds the resolved prices of a product and of its variants, in product variant order.
+type ProductPricing struct {
+	Currency string
+	Price    ResolvedPrice
+	Variants []ResolvedPrice
+}
+
+// PriceBook holds the market prices and active sales of a set of products at a point in time.
+// A nil PriceBook resolves the stored prices without sales.
+type PriceBook struct {
+	Market     *Market
+	listPrices map[priceKey]decimal.Decimal
+	sales      map[saleKey]SalePrice
+}
+
+// priceKey identifies a product price, or a variant price when variantID is not zero.
+type priceKey struct {
+	productID uint
+	variantID uint
+}
+
+// saleKey identifies the sales of a price, either on the market price list or on the stored prices.
+type saleKey struct {
+	priceKey
+	market bool
+}
+
+var marketCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NormalizeMarketCode upper-cases a market code and reports whether it is two letters.
+func NormalizeMarketCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, marketCodePattern.MatchString(code)
+}
+
+// NewPriceBook creates a price book from market prices and sales active at the same point in time.
+// Overlapping sales on the same price keep the lowest one.
+func NewPriceBook(market *Market, listPrices []MarketPrice, sales []SalePrice) *PriceBook {
+	book := &PriceBook{
+		Market:     market,
+		listPrices: make(map[priceKey]decimal.Decimal, len(listPrices)),
+		sales:      make(map[saleKey]SalePrice, len(sales)),
+	}
+
+	for _, price := range listPrices {
+		book.listPrices[newPriceKey(price.ProductID, price.VariantID)] = price.Price
+	}
+
+	for _, sale := range sales {
+		key := saleKey{priceKey: newPriceKey(sale.ProductID, sale.VariantID), market: sale.MarketCode != nil}
+		if existing, ok := book.sales[key]; !ok || sale.Price.LessThan(existing.Price) {
+			book.sales[key] = sale
+		}
+	}
+
+	return book
+}
+
+func newPriceKey(productID uint, variantID *uint) priceKey {
+	key := priceKey{productID: productID}
+	if variantID != nil {
+		key.variantID = *variantID
+	}
+
+	return key
+}
+
+// Resolve returns the prices of a product and its variants.
+// Products listed in the book's market use the market price list and its currency,
+// other products keep their stored prices and currency. Variants without a price of their own
+// inherit the product price, including its sale.
+func (b *PriceBook) Resolve(product Product) ProductPricing {
+	currency := product.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	base := product.Price
+	listed := false
+	if b != nil && b.Market != nil {
+		if price, ok := b.listPrices[priceKey{productID: product.ID}]; ok {
+			base, listed, currency = price, true, b.Market.Currency
+		}
+	}
+
+	pricing := ProductPricing{
+		Currency: currency,
+		Price:    b.applySale(saleKey{priceKey: priceKey{productID: product.ID}, market: listed}, base),
+		Variants: make([]ResolvedPrice, len(product.Variants)),
+	}
+
+	for i, variant := range product.Variants {
+		key := saleKey{priceKey: priceKey{productID: product.ID, variantID: variant.ID}, market: listed}
+		own, hasOwn := b.variantPrice(product.ID, variant, listed)
+		switch {
+		case hasOwn:
+			pricing.Variants[i] = b.applySale(key, own)
+		case b.hasSale(key):
+			pricing.Variants[i] = b.applySale(key, base)
+		default:
+			pricing.Variants[i] = pricing.Price
+		}
+	}
+
+	return pricing
+}
+
+// variantPrice returns the price a variant overrides on the resolved price list.
+func (b *PriceBook) variantPrice(productID uint, variant Variant, listed bool) (decimal.Decimal, bool) {
+	if !listed {
+		if variant.Price == nil {
+			return decimal.Decimal{}, false
+		}
+
+		return *variant.Price, true
+	}
+
+	price, ok := b.listPrices[priceKey{productID: productID, variantID: variant.ID}]
+	return price, ok
+}
+
+func (b *PriceBook) hasSale(key saleKey) bool {
+	if b == nil {
+		return false
+	}
+
+	_, ok := b.sales[key]
+	return ok
+}
+
+// applySale resolves an original price against the active sale of the key, if it is lower.
+func (b *PriceBook) applySale(key saleKey, original decimal.Decimal) ResolvedPrice {
+	resolved := ResolvedPrice{Original: original}
+	if b == nil {
+		return resolved
+	}
+
+	if sale, ok := b.sales[key]; ok && sale.Price.LessThan(original) {
+		price, endsAt := sale.Price, sale.EndsAt
+		resolved.Sale = &price
+		resolved.SaleEndsAt = &endsAt
+	}
+
+	return resolved
+}
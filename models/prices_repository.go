@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrMarketNotFound indicates that no market matches the given code.
+var ErrMarketNotFound = errors.New("market not found")
+
+// PricesRepository provides persistence operations for market price lists and sales.
+type PricesRepository struct {
+	db *gorm.DB
+}
+
+// NewPricesRepository creates a prices repository backed by gorm.
+func NewPricesRepository(db *gorm.DB) *PricesRepository {
+	return &PricesRepository{db: db}
+}
+
+// GetPriceBook loads the prices of the given products in a market at a point in time.
+// An empty market code loads only the sales on stored prices.
+func (r *PricesRepository) GetPriceBook(ctx context.Context, marketCode string, at time.Time, productIDs []uint) (*PriceBook, error) {
+	ctx = withQueryMethod(ctx, "PricesRepository.GetPriceBook")
+
+	db := r.db.WithContext(ctx)
+	var market *Market
+	if marketCode != "" {
+		market = &Market{}
+		if err := db.Where("code = ?", marketCode).First(market).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrMarketNotFound
+			}
+
+			return nil, fmt.Errorf("find market failed: %w", err)
+		}
+	}
+
+	if len(productIDs) == 0 {
+		return NewPriceBook(market, nil, nil), nil
+	}
+
+	var listPrices []MarketPrice
+	if market != nil {
+		if err := db.Where("market_code = ? AND product_id IN ?", market.Code, productIDs).Find(&listPrices).Error; err != nil {
+			return nil, fmt.Errorf("list market prices failed: %w", err)
+		}
+	}
+
+	sales := db.Where("product_id IN ? AND starts_at <= ? AND ends_at > ?", productIDs, at, at)
+	if market != nil {
+		sales = sales.Where("market_code IS NULL OR market_code = ?", market.Code)
+	} else {
+		sales = sales.Where("market_code IS NULL")
+	}
+
+	var activeSales []SalePrice
+	if err := sales.Find(&activeSales).Error; err != nil {
+		return nil, fmt.Errorf("list sale prices failed: %w", err)
+	}
+
+	return NewPriceBook(market, listPrices, activeSales), nil
+}
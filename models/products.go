@@ -1,20 +1,40 @@
 package models
 
 import (
+	"slices"
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
 // Product represents a product stored in the catalog.
 type Product struct {
-	ID         uint            `gorm:"primaryKey"`
-	Code       string          `gorm:"uniqueIndex;not null"`
-	Price      decimal.Decimal `gorm:"type:decimal(10,2);not null"`
-	CategoryID uint            `gorm:"not null"`
-	Category   Category        `gorm:"foreignKey:CategoryID"`
-	Variants   []Variant       `gorm:"foreignKey:ProductID"`
+	ID          uint            `gorm:"primaryKey"`
+	Code        string          `gorm:"uniqueIndex;not null"`
+	Name        string          `gorm:"not null"`
+	Description string          `gorm:"not null"`
+	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	Currency    string          `gorm:"type:char(3);not null;default:EUR"`
+	CategoryID  uint            `gorm:"not null"`
+	Category    Category        `gorm:"foreignKey:CategoryID"`
+	Variants    []Variant       `gorm:"foreignKey:ProductID"`
+	CreatedAt   time.Time
+
+	// SortPrice is the price the product was sorted by in a keyset listing sorted by price,
+	// see ProductCursor. It is not loaded otherwise.
+	SortPrice decimal.Decimal `gorm:"->;-:migration"`
 }
 
 // TableName returns the database table name for Product.
 func (p *Product) TableName() string {
 	return "products"
 }
+
+// reservedProductCodes are the codes that would be shadowed by the routes of the catalog, such as
+// GET /catalog/search, and cannot be reached as products.
+var reservedProductCodes = []string{"search", "export"}
+
+// IsReservedProductCode reports whether code cannot be used as a product code.
+func IsReservedProductCode(code string) bool {
+	return slices.Contains(reservedProductCodes, code)
+}
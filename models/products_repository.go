@@ -1,24 +1,150 @@
 package models
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	// ErrProductCodeAlreadyExists indicates a unique violation for product code.
+	ErrProductCodeAlreadyExists = errors.New("product code already exists")
+
+	// ErrProductNotFound indicates that no product has the requested code.
+	ErrProductNotFound = errors.New("product not found")
+
+	// ErrProductCategoryNotFound indicates that the category assigned to a product does not exist.
+	// It matches ErrCategoryNotFound with errors.Is.
+	ErrProductCategoryNotFound = fmt.Errorf("product %w", ErrCategoryNotFound)
+)
+
+// exportBatchSize is the number of exported products whose associations are loaded together.
+const exportBatchSize = 100
+
 // ProductsRepository provides persistence operations for products.
+// Catalog reads go through reads when it is set and through db otherwise.
 type ProductsRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	reads *ReadRouter
 }
 
+// ProductSortField identifies the column used to order catalog listings.
+type ProductSortField string
+
+const (
+	// SortByID orders products by id, the default catalog order.
+	SortByID ProductSortField = "id"
+	// SortByPrice orders products by price.
+	SortByPrice ProductSortField = "price"
+	// SortByCode orders products by code.
+	SortByCode ProductSortField = "code"
+	// SortByCreatedAt orders products by creation date.
+	SortByCreatedAt ProductSortField = "created_at"
+)
+
+// productSortColumns maps sort fields to their qualified columns. Prices are converted before
+// being sorted, see sortExpression.
+var productSortColumns = map[ProductSortField]string{
+	SortByID:        "products.id",
+	SortByCode:      "products.code",
+	SortByCreatedAt: "products.created_at",
+}
+
+// unconvertiblePriceLast is the ascending sort value of prices without an exchange rate into the
+// price currency, above any price. Descending orders use -1, below any price.
+const unconvertiblePriceLast = "1000000000000000000"
+
 // ProductCatalogFilter defines pagination and filter options for catalog listing.
+// Categories and ExcludeCategories match category codes or names case-insensitively,
+// including all descendant categories. Codes restricts the listing to the given product codes.
+// Price bounds are combined, so setting a lower and an upper bound selects a range.
+// When MatchVariantPrices is true a product matches if the effective price of any of
+// its variants is within the bounds, the effective price being the variant override or
+// the product price; products without variants are matched on their own price.
+// Price bounds, the price sort order and price facets are in PriceCurrency, DefaultCurrency when empty.
+// Prices stored in another currency are converted with the exchange rates, and products whose
+// currency has no rate into PriceCurrency never match a price bound and are sorted last by price.
+// A non-nil InStock keeps only products that have, or do not have, a variant in stock
+// in any warehouse, reserved quantities excluded; products without variants are never in stock.
+// An empty SortBy orders by id. Products with equal sort values are ordered by id
+// in the same direction so that pages are stable.
 type ProductCatalogFilter struct {
-	Offset        int
-	Limit         int
-	Category      string
-	PriceLessThan *decimal.Decimal
+	Offset                  int
+	Limit                   int
+	Categories              []string
+	ExcludeCategories       []string
+	Codes                   []string
+	PriceLessThan           *decimal.Decimal
+	PriceLessThanOrEqual    *decimal.Decimal
+	PriceGreaterThan        *decimal.Decimal
+	PriceGreaterThanOrEqual *decimal.Decimal
+	MatchVariantPrices      bool
+	PriceCurrency           string
+	InStock                 *bool
+	SortBy                  ProductSortField
+	SortDesc                bool
+}
+
+// ProductCursor identifies the last product of a keyset page.
+// Value holds that product's value of the sorted column, Product.SortPrice when sorting by price,
+// and is ignored when sorting by id.
+type ProductCursor struct {
+	ID    uint
+	Value any
+}
+
+// FacetRequest selects the facets computed for a catalog listing.
+// PriceBoundaries are ascending bucket limits; no price facet is computed when it is empty.
+type FacetRequest struct {
+	Categories      bool
+	PriceBoundaries []decimal.Decimal
+}
+
+// ProductFacets holds aggregated counts of the products matching a catalog filter.
+type ProductFacets struct {
+	Categories []CategoryFacet
+	Prices     []PriceFacet
+}
+
+// CategoryFacet counts matching products assigned directly to a category.
+type CategoryFacet struct {
+	Code  string
+	Name  string
+	Count int64
+}
+
+// PriceFacet counts matching products whose price, in the filter's price currency, is in [Min, Max).
+// A nil Min or Max leaves the bucket open on that side.
+type PriceFacet struct {
+	Min   *decimal.Decimal
+	Max   *decimal.Decimal
+	Count int64
+}
+
+// ProductInput defines the writable fields of a new product.
+// An empty Currency stores the price in DefaultCurrency.
+type ProductInput struct {
+	Code         string
+	Name         string
+	Description  string
+	Price        decimal.Decimal
+	Currency     string
+	CategoryCode string
+}
+
+// ProductUpdate defines a partial product update. Nil fields are left untouched.
+type ProductUpdate struct {
+	Name         *string
+	Description  *string
+	Price        *decimal.Decimal
+	Currency     *string
+	CategoryCode *string
 }
 
 // NewProductsRepository creates a products repository backed by gorm.
@@ -28,19 +154,30 @@ func NewProductsRepository(db *gorm.DB) *ProductsRepository {
 	}
 }
 
-// ListProducts returns products and total count according to the provided filter.
-func (r *ProductsRepository) ListProducts(filter ProductCatalogFilter) ([]Product, int64, error) {
-	query := r.db.Model(&Product{})
-
-	if strings.TrimSpace(filter.Category) != "" {
-		category := strings.TrimSpace(filter.Category)
-		query = query.Joins("Category").Where("LOWER(\"Category\".code) = LOWER(?) OR LOWER(\"Category\".name) = LOWER(?)", category, category)
+// NewReplicatedProductsRepository creates a products repository writing to db and routing
+// catalog listings, searches, facets, exports and product lookups through reads.
+func NewReplicatedProductsRepository(db *gorm.DB, reads *ReadRouter) *ProductsRepository {
+	return &ProductsRepository{
+		db:    db,
+		reads: reads,
 	}
+}
 
-	if filter.PriceLessThan != nil {
-		query = query.Where("products.price < ?", *filter.PriceLessThan)
+// reader returns the connection used by catalog reads, bound to ctx.
+func (r *ProductsRepository) reader(ctx context.Context) *gorm.DB {
+	if r.reads == nil {
+		return r.db.WithContext(ctx)
 	}
 
+	return r.reads.Reader().WithContext(ctx)
+}
+
+// ListProducts returns products and total count according to the provided filter.
+func (r *ProductsRepository) ListProducts(ctx context.Context, filter ProductCatalogFilter) ([]Product, int64, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.ListProducts")
+
+	query := catalogQuery(r.reader(ctx), filter)
+
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("count products failed: %w", err)
@@ -49,7 +186,7 @@ func (r *ProductsRepository) ListProducts(filter ProductCatalogFilter) ([]Produc
 	var products []Product
 	if err := query.
 		Preload("Category").
-		Order("products.id ASC").
+		Clauses(catalogOrder(filter)).
 		Offset(filter.Offset).
 		Limit(filter.Limit).
 		Find(&products).Error; err != nil {
@@ -59,12 +196,541 @@ func (r *ProductsRepository) ListProducts(filter ProductCatalogFilter) ([]Produc
 	return products, total, nil
 }
 
+// ListProductsAfter returns up to filter.Limit products that come after the given cursor
+// in the requested sort order, using keyset pagination. A nil cursor returns the first page.
+// Offset is ignored and no total is computed; hasMore reports whether another page exists.
+func (r *ProductsRepository) ListProductsAfter(ctx context.Context, filter ProductCatalogFilter, after *ProductCursor) ([]Product, bool, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.ListProductsAfter")
+
+	query := catalogQuery(r.reader(ctx), filter)
+	expression, args := sortExpression(filter)
+	if filter.SortBy == SortByPrice {
+		query = query.Select("products.*, "+expression+" AS sort_price", args...)
+	}
+
+	if after != nil {
+		operator := ">"
+		if filter.SortDesc {
+			operator = "<"
+		}
+
+		if expression == productSortColumns[SortByID] {
+			query = query.Where("products.id "+operator+" ?", after.ID)
+		} else {
+			query = query.Where("("+expression+", products.id) "+operator+" (?, ?)", append(args, after.Value, after.ID)...)
+		}
+	}
+
+	var products []Product
+	if err := query.
+		Preload("Category").
+		Clauses(catalogOrder(filter)).
+		Limit(filter.Limit + 1).
+		Find(&products).Error; err != nil {
+		return nil, false, fmt.Errorf("list products failed: %w", err)
+	}
+
+	hasMore := len(products) > filter.Limit
+	if hasMore {
+		products = products[:filter.Limit]
+	}
+
+	return products, hasMore, nil
+}
+
+// SearchProducts returns products matching the full-text query and filter, ordered by relevance.
+// The query uses web search syntax and is matched against product names, codes and descriptions.
+// Sorting options of the filter are ignored in favour of the relevance ranking.
+func (r *ProductsRepository) SearchProducts(ctx context.Context, text string, filter ProductCatalogFilter) ([]Product, int64, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.SearchProducts")
+
+	query := catalogQuery(r.reader(ctx), filter).
+		Where("products.search_vector @@ websearch_to_tsquery('english', ?)", text)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count search results failed: %w", err)
+	}
+
+	var products []Product
+	if err := query.
+		Preload("Category").
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(products.search_vector, websearch_to_tsquery('english', ?)) DESC, products.id ASC",
+			Vars:               []any{text},
+			WithoutParentheses: true,
+		}}).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&products).Error; err != nil {
+		return nil, 0, fmt.Errorf("search products failed: %w", err)
+	}
+
+	return products, total, nil
+}
+
+// ExportProducts passes every product matching the filter to fn in catalog order, with category and
+// variants loaded. Offset and Limit are ignored. The export runs in one read-only REPEATABLE READ
+// transaction, so that it sees a single snapshot, and holds a single connection. Products are fetched
+// from a server-side cursor in batches of exportBatchSize products, whose associations are loaded
+// together, so memory use does not grow with the catalog. An error returned by fn stops the export and
+// is returned as is.
+func (r *ProductsRepository) ExportProducts(ctx context.Context, filter ProductCatalogFilter, fn func(Product) error) error {
+	ctx = withQueryMethod(ctx, "ProductsRepository.ExportProducts")
+
+	return r.reader(ctx).Transaction(func(tx *gorm.DB) error {
+		query := catalogQuery(tx, filter).Select("products.*").Clauses(catalogOrder(filter))
+		if err := tx.Exec("DECLARE catalog_export NO SCROLL CURSOR FOR ?", query).Error; err != nil {
+			return fmt.Errorf("export products failed: %w", err)
+		}
+
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM catalog_export", exportBatchSize)
+		for {
+			var batch []Product
+			if err := tx.Raw(fetch).Scan(&batch).Error; err != nil {
+				return fmt.Errorf("fetch exported products failed: %w", err)
+			}
+
+			if err := exportBatch(tx, batch, fn); err != nil {
+				return err
+			}
+
+			if len(batch) < exportBatchSize {
+				return nil
+			}
+		}
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// exportBatch loads the categories and variants of a batch of exported products and passes them to fn.
+func exportBatch(db *gorm.DB, products []Product, fn func(Product) error) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, len(products))
+	categoryIDs := make([]uint, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+		categoryIDs[i] = product.CategoryID
+	}
+
+	var categories []Category
+	if err := db.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return fmt.Errorf("load exported categories failed: %w", err)
+	}
+
+	var variants []Variant
+	if err := db.Where("product_id IN ?", productIDs).Order("id ASC").Find(&variants).Error; err != nil {
+		return fmt.Errorf("load exported variants failed: %w", err)
+	}
+
+	categoriesByID := make(map[uint]Category, len(categories))
+	for _, category := range categories {
+		categoriesByID[category.ID] = category
+	}
+
+	variantsByProduct := make(map[uint][]Variant, len(products))
+	for _, variant := range variants {
+		variantsByProduct[variant.ProductID] = append(variantsByProduct[variant.ProductID], variant)
+	}
+
+	for _, product := range products {
+		product.Category = categoriesByID[product.CategoryID]
+		product.Variants = variantsByProduct[product.ID]
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetProductFacets aggregates the products matching the filter into the requested facets.
+// Price buckets are based on the product price converted into the filter's price currency and include
+// empty buckets. Products without an exchange rate into that currency are in no price bucket.
+func (r *ProductsRepository) GetProductFacets(ctx context.Context, filter ProductCatalogFilter, request FacetRequest) (*ProductFacets, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.GetProductFacets")
+
+	db := r.reader(ctx)
+	facets := &ProductFacets{}
+
+	if request.Categories {
+		if err := catalogQuery(db, filter).
+			Select("categories.code AS code, categories.name AS name, COUNT(*) AS count").
+			Joins("JOIN categories ON categories.id = products.category_id").
+			Group("categories.id, categories.code, categories.name").
+			Order("categories.code ASC").
+			Scan(&facets.Categories).Error; err != nil {
+			return nil, fmt.Errorf("count category facets failed: %w", err)
+		}
+	}
+
+	if len(request.PriceBoundaries) > 0 {
+		boundaries := request.PriceBoundaries
+		var bucket strings.Builder
+		args := make([]any, len(boundaries))
+		bucket.WriteString("CASE WHEN priced.price IS NULL THEN -1")
+		for i, boundary := range boundaries {
+			fmt.Fprintf(&bucket, " WHEN priced.price < ? THEN %d", i)
+			args[i] = boundary
+		}
+		fmt.Fprintf(&bucket, " ELSE %d END", len(boundaries))
+
+		price, priceArgs := convertedPrice("products.price", filter.priceCurrency())
+		var rows []struct {
+			Bucket int
+			Count  int64
+		}
+		if err := db.
+			Table("(?) AS priced", catalogQuery(db, filter).Select(price+" AS price", priceArgs...)).
+			Select(bucket.String()+" AS bucket, COUNT(*) AS count", args...).
+			Group("bucket").
+			Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("count price facets failed: %w", err)
+		}
+
+		facets.Prices = make([]PriceFacet, len(boundaries)+1)
+		for i := range facets.Prices {
+			if i > 0 {
+				facets.Prices[i].Min = &boundaries[i-1]
+			}
+
+			if i < len(boundaries) {
+				facets.Prices[i].Max = &boundaries[i]
+			}
+		}
+
+		for _, row := range rows {
+			if row.Bucket >= 0 && row.Bucket < len(facets.Prices) {
+				facets.Prices[row.Bucket].Count = row.Count
+			}
+		}
+	}
+
+	return facets, nil
+}
+
 // GetProductByCode returns a single product by code with category and variants preloaded.
-func (r *ProductsRepository) GetProductByCode(code string) (*Product, error) {
+// Variants are ordered by id and their stock levels by warehouse.
+func (r *ProductsRepository) GetProductByCode(ctx context.Context, code string) (*Product, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.GetProductByCode")
+
 	var product Product
-	if err := r.db.Preload("Category").Preload("Variants").Where("code = ?", code).First(&product).Error; err != nil {
+	if err := preloadProductDetails(r.reader(ctx)).Where("code = ?", code).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+
 		return nil, fmt.Errorf("get product by code failed: %w", err)
 	}
 
 	return &product, nil
 }
+
+// CreateProduct persists a new product linked to the category with the given code.
+func (r *ProductsRepository) CreateProduct(ctx context.Context, input ProductInput) (*Product, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.CreateProduct")
+
+	var product Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		category, err := findProductCategory(tx, input.CategoryCode)
+		if err != nil {
+			return err
+		}
+
+		currency := input.Currency
+		if currency == "" {
+			currency = DefaultCurrency
+		}
+
+		product = Product{
+			Code:        input.Code,
+			Name:        input.Name,
+			Description: input.Description,
+			Price:       input.Price,
+			Currency:    currency,
+			CategoryID:  category.ID,
+		}
+		if err := tx.Omit(clause.Associations).Create(&product).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrProductCodeAlreadyExists
+			}
+
+			return fmt.Errorf("create product failed: %w", err)
+		}
+
+		return loadProductDetails(tx, &product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// UpdateProduct applies a partial update to the product with the given code.
+func (r *ProductsRepository) UpdateProduct(ctx context.Context, code string, update ProductUpdate) (*Product, error) {
+	ctx = withQueryMethod(ctx, "ProductsRepository.UpdateProduct")
+
+	var product Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("code = ?", code).First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+
+			return fmt.Errorf("update product failed: %w", err)
+		}
+
+		changes := map[string]any{}
+		if update.Name != nil {
+			changes["name"] = *update.Name
+		}
+
+		if update.Description != nil {
+			changes["description"] = *update.Description
+		}
+
+		if update.Price != nil {
+			changes["price"] = *update.Price
+		}
+
+		if update.Currency != nil {
+			changes["currency"] = *update.Currency
+		}
+
+		if update.CategoryCode != nil {
+			category, err := findProductCategory(tx, *update.CategoryCode)
+			if err != nil {
+				return err
+			}
+			changes["category_id"] = category.ID
+		}
+
+		if len(changes) > 0 {
+			if err := tx.Model(&product).Updates(changes).Error; err != nil {
+				return fmt.Errorf("update product failed: %w", err)
+			}
+		}
+
+		return loadProductDetails(tx, &product)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// DeleteProduct removes the product with the given code along with its variants.
+func (r *ProductsRepository) DeleteProduct(ctx context.Context, code string) error {
+	ctx = withQueryMethod(ctx, "ProductsRepository.DeleteProduct")
+
+	result := r.db.WithContext(ctx).Where("code = ?", code).Delete(&Product{})
+	if result.Error != nil {
+		return fmt.Errorf("delete product failed: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrProductNotFound
+	}
+
+	return nil
+}
+
+// findProductCategory returns the category with the given code, or ErrProductCategoryNotFound.
+func findProductCategory(db *gorm.DB, code string) (*Category, error) {
+	category, err := findCategoryByCode(db, code)
+	if errors.Is(err, ErrCategoryNotFound) {
+		return nil, ErrProductCategoryNotFound
+	}
+
+	return category, err
+}
+
+// catalogQuery builds the filtered products query shared by the catalog listings on the given connection.
+func catalogQuery(db *gorm.DB, filter ProductCatalogFilter) *gorm.DB {
+	query := db.Model(&Product{})
+
+	if len(filter.Categories) > 0 {
+		query = query.Where("products.category_id IN (?)", matchingCategorySubtree(db, filter.Categories))
+	}
+
+	if len(filter.ExcludeCategories) > 0 {
+		query = query.Where("products.category_id NOT IN (?)", matchingCategorySubtree(db, filter.ExcludeCategories))
+	}
+
+	if len(filter.Codes) > 0 {
+		query = query.Where("products.code IN ?", filter.Codes)
+	}
+
+	if filter.MatchVariantPrices {
+		price, priceArgs := convertedPrice("effective.price", filter.priceCurrency())
+		if condition, args := priceConditions(filter, price, priceArgs); condition != "" {
+			query = query.Where(`EXISTS (
+	SELECT 1 FROM (
+		SELECT COALESCE(product_variants.price, products.price) AS price
+		FROM product_variants WHERE product_variants.product_id = products.id
+		UNION ALL
+		SELECT products.price WHERE NOT EXISTS (
+			SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id
+		)
+	) AS effective WHERE `+condition+`
+)`, args...)
+		}
+	} else {
+		price, priceArgs := convertedPrice("products.price", filter.priceCurrency())
+		if condition, args := priceConditions(filter, price, priceArgs); condition != "" {
+			query = query.Where(condition, args...)
+		}
+	}
+
+	if filter.InStock != nil {
+		if *filter.InStock {
+			query = query.Where("EXISTS (?)", productStockScope(db))
+		} else {
+			query = query.Where("NOT EXISTS (?)", productStockScope(db))
+		}
+	}
+
+	return query
+}
+
+// productStockScope selects the in-stock variants of the product in the enclosing query.
+func productStockScope(db *gorm.DB) *gorm.DB {
+	return db.Model(&StockLevel{}).
+		Select("1").
+		Joins("JOIN product_variants ON product_variants.id = stock_levels.variant_id").
+		Where("product_variants.product_id = products.id AND stock_levels.quantity > stock_levels.reserved")
+}
+
+// matchingCategorySubtree selects the ids of the categories whose code or name matches one of
+// the given values case-insensitively, along with all their descendants.
+func matchingCategorySubtree(db *gorm.DB, categories []string) *gorm.DB {
+	lowered := make([]string, len(categories))
+	for i, category := range categories {
+		lowered[i] = strings.ToLower(strings.TrimSpace(category))
+	}
+
+	return categorySubtreeIDs(db, "LOWER(code) IN ? OR LOWER(name) IN ?", lowered, lowered)
+}
+
+// priceConditions returns the SQL conditions restricting the price expression, with its arguments,
+// to the filter's price bounds. It returns an empty condition when no bound is set.
+func priceConditions(filter ProductCatalogFilter, price string, priceArgs []any) (string, []any) {
+	bounds := []struct {
+		operator string
+		value    *decimal.Decimal
+	}{
+		{"<", filter.PriceLessThan},
+		{"<=", filter.PriceLessThanOrEqual},
+		{">", filter.PriceGreaterThan},
+		{">=", filter.PriceGreaterThanOrEqual},
+	}
+
+	var conditions []string
+	var args []any
+	for _, bound := range bounds {
+		if bound.value == nil {
+			continue
+		}
+
+		conditions = append(conditions, price+" "+bound.operator+" ?")
+		args = append(args, priceArgs...)
+		args = append(args, *bound.value)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// priceCurrency returns the currency of the filter's price bounds and price sort order.
+func (f ProductCatalogFilter) priceCurrency() string {
+	if f.PriceCurrency == "" {
+		return DefaultCurrency
+	}
+
+	return f.PriceCurrency
+}
+
+// convertedPrice returns the SQL expression converting the amount in column, in the currency of the
+// enclosing products row, into currency, with its arguments. Like ExchangeRatesRepository.GetExchangeRate
+// it falls back to the inverse of the opposite rate, and it is NULL when no rate converts the amount.
+func convertedPrice(column, currency string) (string, []any) {
+	return "(" + column + ` * CASE WHEN products.currency = ? THEN 1 ELSE COALESCE(
+	(SELECT exchange_rates.rate FROM exchange_rates
+		WHERE exchange_rates.base_currency = products.currency AND exchange_rates.quote_currency = ?),
+	(SELECT 1 / exchange_rates.rate FROM exchange_rates
+		WHERE exchange_rates.base_currency = ? AND exchange_rates.quote_currency = products.currency)
+) END)`, []any{currency, currency, currency}
+}
+
+// sortExpression returns the SQL expression the filter sorts by, with its arguments, defaulting to products.id.
+// Prices are sorted in the price currency, and prices without an exchange rate into it are sorted last.
+func sortExpression(filter ProductCatalogFilter) (string, []any) {
+	if filter.SortBy != SortByPrice {
+		if column, ok := productSortColumns[filter.SortBy]; ok {
+			return column, nil
+		}
+
+		return productSortColumns[SortByID], nil
+	}
+
+	last := unconvertiblePriceLast
+	if filter.SortDesc {
+		last = "-1"
+	}
+
+	price, args := convertedPrice("products.price", filter.priceCurrency())
+	return "COALESCE(" + price + ", " + last + ")", args
+}
+
+// catalogOrder returns the ORDER BY clause for the filter, using products.id as tie-breaker.
+func catalogOrder(filter ProductCatalogFilter) clause.OrderBy {
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	expression, args := sortExpression(filter)
+	order := expression + " " + direction
+	if expression != productSortColumns[SortByID] {
+		order += ", products.id " + direction
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{SQL: order, Vars: args, WithoutParentheses: true}}
+}
+
+// findProductByCode looks up a product by its exact code without preloading associations.
+// It returns ErrProductNotFound when no product has the code.
+func findProductByCode(db *gorm.DB, code string) (*Product, error) {
+	var product Product
+	if err := db.Where("code = ?", code).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+
+		return nil, fmt.Errorf("find product failed: %w", err)
+	}
+
+	return &product, nil
+}
+
+// loadProductDetails reloads a product with its category and variants preloaded.
+func loadProductDetails(db *gorm.DB, product *Product) error {
+	if err := preloadProductDetails(db).First(product, product.ID).Error; err != nil {
+		return fmt.Errorf("load product failed: %w", err)
+	}
+
+	return nil
+}
+
+// preloadProductDetails preloads the category and variants of the queried products, with variants
+// ordered by id and stock levels by warehouse like ListVariants and ListStock, so that responses
+// list them in a stable order.
+func preloadProductDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Category").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Variants.StockLevels", func(db *gorm.DB) *gorm.DB { return db.Order("warehouse ASC") })
+}
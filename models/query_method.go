@@ -0,0 +1,19 @@
+package models
+
+import "context"
+
+// queryMethodKey is the context key of the repository method running the queries.
+type queryMethodKey struct{}
+
+// withQueryMethod labels the queries run with the returned context with the repository method,
+// such as "ProductsRepository.ListProducts". Methods called by another method relabel their queries.
+func withQueryMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, queryMethodKey{}, method)
+}
+
+// QueryMethod returns the repository method running the queries of ctx, as "Type.Method".
+// It reports false for queries not run by a repository method.
+func QueryMethod(ctx context.Context) (string, bool) {
+	method, ok := ctx.Value(queryMethodKey{}).(string)
+	return method, ok
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestRepositoryMethodsLabelTheirQueries(t *testing.T) {
+	t.Parallel()
+
+	db := openDryRunDB(t)
+	var methods []string
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:query_method", func(db *gorm.DB) {
+		method, ok := QueryMethod(db.Statement.Context)
+		assert.True(t, ok)
+		methods = append(methods, method)
+	}))
+
+	_, _ = NewProductsRepository(db).GetProductByCode(t.Context(), "PROD001")
+	_, _ = NewCategoriesRepository(db).GetAllCategories(t.Context())
+
+	require.NotEmpty(t, methods)
+	assert.Equal(t, "ProductsRepository.GetProductByCode", methods[0])
+	assert.Equal(t, "CategoriesRepository.GetAllCategories", methods[len(methods)-1])
+
+	_, ok := QueryMethod(t.Context())
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReadRouter picks the connection used by catalog reads. Reads are spread round-robin over the
+// replicas, and go to the primary when there are no replicas or while the primary read window
+// that follows every write through the primary is open, so that recent writes are visible.
+type ReadRouter struct {
+	primary           *gorm.DB
+	replicas          []*gorm.DB
+	primaryReadWindow time.Duration
+	next              atomic.Uint64
+	lastWrite         atomic.Int64
+}
+
+// NewReadRouter creates a router over the primary and its replicas. A positive primaryReadWindow
+// registers callbacks on the primary recording the time of every successful create, update and delete.
+// Raw statements are not recorded since the repositories only run reads with them, such as the cursor
+// of ExportProducts; a repository writing with a raw statement must call MarkWrite itself.
+func NewReadRouter(primary *gorm.DB, replicas []*gorm.DB, primaryReadWindow time.Duration) (*ReadRouter, error) {
+	router := &ReadRouter{primary: primary, replicas: replicas, primaryReadWindow: primaryReadWindow}
+	if len(replicas) > 0 && primaryReadWindow > 0 {
+		markWrite := func(db *gorm.DB) {
+			if db.Error == nil {
+				router.MarkWrite()
+			}
+		}
+		callbacks := primary.Callback()
+		if err := errors.Join(
+			callbacks.Create().After("gorm:create").Register("models:mark_write", markWrite),
+			callbacks.Update().After("gorm:update").Register("models:mark_write", markWrite),
+			callbacks.Delete().After("gorm:delete").Register("models:mark_write", markWrite),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return router, nil
+}
+
+// MarkWrite opens the primary read window.
+func (r *ReadRouter) MarkWrite() {
+	r.lastWrite.Store(time.Now().UnixNano())
+}
+
+// Reader returns the connection for the next read.
+func (r *ReadRouter) Reader() *gorm.DB {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+
+	if r.primaryReadWindow > 0 && time.Since(time.Unix(0, r.lastWrite.Load())) < r.primaryReadWindow {
+		return r.primary
+	}
+
+	return r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+}
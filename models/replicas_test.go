@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func openDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	return db
+}
+
+func TestReadRouter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without replicas reads from the primary", func(t *testing.T) {
+		primary := openDryRunDB(t)
+		router, err := NewReadRouter(primary, nil, time.Minute)
+		require.NoError(t, err)
+
+		assert.Same(t, primary, router.Reader())
+	})
+
+	t.Run("spreads reads over replicas", func(t *testing.T) {
+		primary, first, second := openDryRunDB(t), openDryRunDB(t), openDryRunDB(t)
+		router, err := NewReadRouter(primary, []*gorm.DB{first, second}, 0)
+		require.NoError(t, err)
+
+		assert.Same(t, first, router.Reader())
+		assert.Same(t, second, router.Reader())
+		assert.Same(t, first, router.Reader())
+
+		router.MarkWrite()
+		assert.Same(t, second, router.Reader())
+	})
+
+	t.Run("reads from the primary after a write", func(t *testing.T) {
+		primary, replica := openDryRunDB(t), openDryRunDB(t)
+		router, err := NewReadRouter(primary, []*gorm.DB{replica}, time.Minute)
+		require.NoError(t, err)
+		assert.Same(t, replica, router.Reader())
+
+		require.NoError(t, primary.Exec("DECLARE catalog_export NO SCROLL CURSOR FOR SELECT 1").Error)
+		assert.Same(t, replica, router.Reader())
+
+		require.NoError(t, primary.Create(&Category{Code: "BOOTS", Name: "Boots"}).Error)
+		assert.Same(t, primary, router.Reader())
+
+		router.lastWrite.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+		assert.Same(t, replica, router.Reader())
+	})
+}
+
+func TestReplicatedRepositoriesRouteReads(t *testing.T) {
+	t.Parallel()
+
+	primary, replica := openDryRunDB(t), openDryRunDB(t)
+	router, err := NewReadRouter(primary, []*gorm.DB{replica}, 0)
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	assert.Same(t, replica.ConnPool, NewReplicatedProductsRepository(primary, router).reader(ctx).ConnPool)
+	assert.Same(t, replica.ConnPool, NewReplicatedCategoriesRepository(primary, router).reader(ctx).ConnPool)
+	assert.Same(t, primary.ConnPool, NewProductsRepository(primary).reader(ctx).ConnPool)
+	assert.Same(t, primary.ConnPool, NewCategoriesRepository(primary).reader(ctx).ConnPool)
+	assert.Equal(t, ctx, NewProductsRepository(primary).reader(ctx).Statement.Context)
+}
@@ -1,15 +1,17 @@
 package models
 
 import (
+	"context"
 	"errors"
-	"os"
-	"path/filepath"
+	"slices"
 	"sort"
-	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/migrate"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -21,33 +23,16 @@ func setupDBWithSeed(t *testing.T) *gorm.DB {
 
 	_ = godotenv.Load("../.env")
 
-	db, closeFn := database.New(
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-	)
+	cfg, err := database.ConfigFromEnv()
+	require.NoError(t, err)
+
+	db, closeFn, err := database.New(cfg)
+	require.NoError(t, err)
 	t.Cleanup(func() {
 		require.NoError(t, closeFn())
 	})
 
-	sqlFiles, err := os.ReadDir("../sql")
-	require.NoError(t, err)
-
-	var names []string
-	for _, entry := range sqlFiles {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
-			continue
-		}
-		names = append(names, entry.Name())
-	}
-	sort.Strings(names)
-
-	for _, name := range names {
-		content, readErr := os.ReadFile(filepath.Join("..", "sql", name))
-		require.NoError(t, readErr)
-		require.NoError(t, db.Exec(string(content)).Error)
-	}
+	require.NoError(t, migrate.Seed(db, "../sql"))
 
 	return db
 }
@@ -58,25 +43,31 @@ func TestTableNames(t *testing.T) {
 	assert.Equal(t, "products", (&Product{}).TableName())
 	assert.Equal(t, "product_variants", (&Variant{}).TableName())
 	assert.Equal(t, "categories", (&Category{}).TableName())
+	assert.Equal(t, "exchange_rates", (&ExchangeRate{}).TableName())
+	assert.Equal(t, "markets", (&Market{}).TableName())
+	assert.Equal(t, "market_prices", (&MarketPrice{}).TableName())
+	assert.Equal(t, "sale_prices", (&SalePrice{}).TableName())
+	assert.Equal(t, "stock_levels", (&StockLevel{}).TableName())
+	assert.Equal(t, "stock_reservations", (&StockReservation{}).TableName())
 }
 
 func TestCategoriesRepositoryCreateAndList(t *testing.T) {
 	db := setupDBWithSeed(t)
 	repo := NewCategoriesRepository(db)
 
-	list, err := repo.GetAllCategories()
+	list, err := repo.GetAllCategories(t.Context())
 	require.NoError(t, err)
 	assert.Len(t, list, 3)
 
-	created, err := repo.CreateCategory(Category{Code: "BAGS", Name: "Bags"})
+	created, err := repo.CreateCategory(t.Context(), CategoryInput{Code: "BAGS", Name: "Bags"})
 	require.NoError(t, err)
 	assert.NotZero(t, created.ID)
 
-	list, err = repo.GetAllCategories()
+	list, err = repo.GetAllCategories(t.Context())
 	require.NoError(t, err)
 	assert.Len(t, list, 4)
 
-	_, err = repo.CreateCategory(Category{Code: "BAGS", Name: "Bags Duplicate"})
+	_, err = repo.CreateCategory(t.Context(), CategoryInput{Code: "BAGS", Name: "Bags Duplicate"})
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrCategoryCodeAlreadyExists))
 }
@@ -87,10 +78,10 @@ func TestCategoriesRepositoryErrorBranches(t *testing.T) {
 
 	require.NoError(t, db.Exec("DROP TABLE categories CASCADE").Error)
 
-	_, err := repo.GetAllCategories()
+	_, err := repo.GetAllCategories(t.Context())
 	assert.Error(t, err)
 
-	_, err = repo.CreateCategory(Category{Code: "X", Name: "X"})
+	_, err = repo.CreateCategory(t.Context(), CategoryInput{Code: "X", Name: "X"})
 	assert.Error(t, err)
 }
 
@@ -98,52 +89,819 @@ func TestProductsRepositoryListProductsAndFilters(t *testing.T) {
 	db := setupDBWithSeed(t)
 	repo := NewProductsRepository(db)
 
-	products, total, err := repo.ListProducts(ProductCatalogFilter{Offset: 0, Limit: 10})
+	products, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10})
 	require.NoError(t, err)
 	assert.Equal(t, int64(8), total)
 	assert.Len(t, products, 8)
 
-	products, total, err = repo.ListProducts(ProductCatalogFilter{Offset: 1, Limit: 2})
+	products, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Offset: 1, Limit: 2})
 	require.NoError(t, err)
 	assert.Equal(t, int64(8), total)
 	assert.Len(t, products, 2)
 
-	products, total, err = repo.ListProducts(ProductCatalogFilter{Offset: 0, Limit: 10, Category: "Shoes"})
+	products, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10, Categories: []string{"Shoes"}})
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), total)
 	assert.Len(t, products, 2)
 
 	price := decimal.RequireFromString("10")
-	products, total, err = repo.ListProducts(ProductCatalogFilter{Offset: 0, Limit: 10, PriceLessThan: &price})
+	products, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10, PriceLessThan: &price})
 	require.NoError(t, err)
 	assert.Equal(t, int64(3), total)
 	assert.Len(t, products, 3)
 }
 
+func TestProductsRepositoryListProductsAfter(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	first, hasMore, err := repo.ListProductsAfter(t.Context(), ProductCatalogFilter{Limit: 5}, nil)
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	require.Len(t, first, 5)
+	assert.Equal(t, "PROD001", first[0].Code)
+
+	second, hasMore, err := repo.ListProductsAfter(t.Context(), ProductCatalogFilter{Limit: 5}, &ProductCursor{ID: first[4].ID})
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, second, 3)
+	assert.Equal(t, "PROD006", second[0].Code)
+
+	shoes, hasMore, err := repo.ListProductsAfter(t.Context(), ProductCatalogFilter{Limit: 5, Categories: []string{"SHOES"}}, nil)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, shoes, 2)
+}
+
+func TestProductsRepositoryListFilters(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	_, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, Categories: []string{"shoes", "Accessories"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+
+	_, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, ExcludeCategories: []string{"CLOTHING"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+
+	products, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, Codes: []string{"PROD001", "PROD003", "MISSING"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "PROD001", products[0].Code)
+	assert.Equal(t, "PROD003", products[1].Code)
+}
+
+func TestProductsRepositoryPriceFilters(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	low := decimal.RequireFromString("9.99")
+	high := decimal.RequireFromString("15.00")
+	_, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, PriceGreaterThanOrEqual: &low, PriceLessThanOrEqual: &high})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+
+	_, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, PriceGreaterThan: &low, PriceLessThan: &high})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+
+	variantLow := decimal.RequireFromString("16.50")
+	variantHigh := decimal.RequireFromString("17.00")
+	products, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{
+		Limit:                   10,
+		PriceGreaterThanOrEqual: &variantLow,
+		PriceLessThanOrEqual:    &variantHigh,
+		MatchVariantPrices:      true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "PROD004", products[0].Code)
+}
+
+func TestProductsRepositorySorting(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	products, _, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, SortBy: SortByPrice})
+	require.NoError(t, err)
+	require.Len(t, products, 8)
+	assert.Equal(t, "PROD006", products[0].Code)
+	assert.Equal(t, "PROD005", products[7].Code)
+
+	products, _, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, SortBy: SortByCode, SortDesc: true})
+	require.NoError(t, err)
+	assert.Equal(t, "PROD008", products[0].Code)
+
+	first, hasMore, err := repo.ListProductsAfter(t.Context(), ProductCatalogFilter{Limit: 3, SortBy: SortByPrice, SortDesc: true}, nil)
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	require.Len(t, first, 3)
+	assert.Equal(t, "PROD005", first[0].Code)
+
+	last := first[2]
+	second, _, err := repo.ListProductsAfter(t.Context(),
+		ProductCatalogFilter{Limit: 3, SortBy: SortByPrice, SortDesc: true},
+		&ProductCursor{ID: last.ID, Value: last.SortPrice},
+	)
+	require.NoError(t, err)
+	require.Len(t, second, 3)
+	assert.True(t, last.SortPrice.Equal(last.Price))
+	assert.True(t, second[0].Price.LessThan(last.Price))
+}
+
+func TestProductsRepositoryPricesAcrossCurrencies(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	require.NoError(t, db.Exec("UPDATE products SET currency = 'GBP' WHERE code = 'PROD006'").Error)
+	require.NoError(t, db.Exec("UPDATE products SET currency = 'JPY' WHERE code = 'PROD003'").Error)
+
+	low := decimal.RequireFromString("6")
+	high := decimal.RequireFromString("9")
+	products, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, PriceGreaterThanOrEqual: &low, PriceLessThanOrEqual: &high})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "PROD006", products[0].Code)
+
+	_, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, PriceLessThanOrEqual: &low, PriceCurrency: "GBP"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	products, _, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, SortBy: SortByPrice})
+	require.NoError(t, err)
+	require.Len(t, products, 8)
+	assert.Equal(t, "PROD006", products[0].Code)
+	assert.Equal(t, "PROD003", products[7].Code)
+
+	products, _, err = repo.ListProductsAfter(t.Context(), ProductCatalogFilter{Limit: 10, SortBy: SortByPrice, SortDesc: true}, nil)
+	require.NoError(t, err)
+	require.Len(t, products, 8)
+	assert.Equal(t, "PROD003", products[7].Code)
+	assert.True(t, products[6].SortPrice.Round(2).Equal(decimal.RequireFromString("6.47")))
+
+	facets, err := repo.GetProductFacets(t.Context(), ProductCatalogFilter{}, FacetRequest{
+		PriceBoundaries: []decimal.Decimal{decimal.NewFromInt(10)},
+	})
+	require.NoError(t, err)
+	require.Len(t, facets.Prices, 2)
+	assert.Equal(t, int64(2), facets.Prices[0].Count)
+	assert.Equal(t, int64(5), facets.Prices[1].Count)
+}
+
+func TestProductsRepositorySearch(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	products, total, err := repo.SearchProducts(t.Context(), "leather", ProductCatalogFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, products, 3)
+
+	products, total, err = repo.SearchProducts(t.Context(), "leather", ProductCatalogFilter{Limit: 10, Categories: []string{"SHOES"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "PROD002", products[0].Code)
+
+	_, total, err = repo.SearchProducts(t.Context(), "PROD003", ProductCatalogFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	created, err := repo.CreateProduct(t.Context(), ProductInput{
+		Code:         "PROD009",
+		Name:         "Velvet Evening Gown",
+		Price:        decimal.NewFromInt(99),
+		CategoryCode: "CLOTHING",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Velvet Evening Gown", created.Name)
+
+	products, _, err = repo.SearchProducts(t.Context(), "velvet gowns", ProductCatalogFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "PROD009", products[0].Code)
+}
+
+func TestProductsRepositoryFacets(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	price := decimal.NewFromInt(20)
+	facets, err := repo.GetProductFacets(t.Context(), ProductCatalogFilter{PriceLessThan: &price}, FacetRequest{
+		Categories:      true,
+		PriceBoundaries: []decimal.Decimal{decimal.NewFromInt(10), decimal.NewFromInt(15)},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, facets.Categories, 3)
+	assert.Equal(t, "ACCESSORIES", facets.Categories[0].Code)
+	assert.Equal(t, int64(2), facets.Categories[0].Count)
+	assert.Equal(t, "CLOTHING", facets.Categories[1].Code)
+	assert.Equal(t, int64(3), facets.Categories[1].Count)
+
+	require.Len(t, facets.Prices, 3)
+	assert.Nil(t, facets.Prices[0].Min)
+	assert.Equal(t, int64(3), facets.Prices[0].Count)
+	assert.Equal(t, int64(2), facets.Prices[1].Count)
+	assert.Nil(t, facets.Prices[2].Max)
+	assert.Equal(t, int64(2), facets.Prices[2].Count)
+}
+
 func TestProductsRepositoryErrorBranches(t *testing.T) {
 	db := setupDBWithSeed(t)
 	repo := NewProductsRepository(db)
 
 	require.NoError(t, db.Exec("DROP TABLE products CASCADE").Error)
 
-	_, _, err := repo.ListProducts(ProductCatalogFilter{Offset: 0, Limit: 10})
+	_, _, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10})
 	assert.Error(t, err)
 
-	_, err = repo.GetProductByCode("PROD001")
+	_, err = repo.GetProductByCode(t.Context(), "PROD001")
 	assert.Error(t, err)
 }
 
+func TestRepositoriesHonourContext(t *testing.T) {
+	db := setupDBWithSeed(t)
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, _, err := NewProductsRepository(db).ListProducts(canceled, ProductCatalogFilter{Limit: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = NewCategoriesRepository(db).CreateCategory(canceled, CategoryInput{Code: "CANCELED", Name: "Canceled"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	expired, cancel := context.WithTimeout(t.Context(), time.Millisecond)
+	defer cancel()
+
+	err = db.WithContext(expired).Exec("SELECT pg_sleep(1)").Error
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = NewCategoriesRepository(db).GetCategoryByCode(t.Context(), "CANCELED")
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
 func TestProductsRepositoryGetByCode(t *testing.T) {
 	db := setupDBWithSeed(t)
 	repo := NewProductsRepository(db)
 
-	product, err := repo.GetProductByCode("PROD001")
+	product, err := repo.GetProductByCode(t.Context(), "PROD001")
 	require.NoError(t, err)
 	assert.Equal(t, "PROD001", product.Code)
 	assert.Equal(t, "CLOTHING", product.Category.Code)
 	assert.NotEmpty(t, product.Variants)
 
-	_, err = repo.GetProductByCode("MISSING")
+	_, err = repo.GetProductByCode(t.Context(), "MISSING")
 	assert.Error(t, err)
-	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+}
+
+func TestProductsRepositoryCreateUpdateDelete(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	created, err := repo.CreateProduct(t.Context(), ProductInput{
+		Code:         "PROD009",
+		Price:        decimal.RequireFromString("19.90"),
+		CategoryCode: "SHOES",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "SHOES", created.Category.Code)
+	assert.Empty(t, created.Variants)
+
+	_, err = repo.CreateProduct(t.Context(), ProductInput{Code: "PROD009", Price: decimal.NewFromInt(1), CategoryCode: "SHOES"})
+	assert.True(t, errors.Is(err, ErrProductCodeAlreadyExists))
+
+	_, err = repo.CreateProduct(t.Context(), ProductInput{Code: "PROD010", Price: decimal.NewFromInt(1), CategoryCode: "MISSING"})
+	assert.True(t, errors.Is(err, ErrProductCategoryNotFound))
+	assert.True(t, errors.Is(err, ErrCategoryNotFound))
+
+	price := decimal.RequireFromString("21.00")
+	category := "ACCESSORIES"
+	updated, err := repo.UpdateProduct(t.Context(), "PROD009", ProductUpdate{Price: &price, CategoryCode: &category})
+	require.NoError(t, err)
+	assert.True(t, price.Equal(updated.Price))
+	assert.Equal(t, "ACCESSORIES", updated.Category.Code)
+
+	_, err = repo.UpdateProduct(t.Context(), "MISSING", ProductUpdate{Price: &price})
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	require.NoError(t, repo.DeleteProduct(t.Context(), "PROD009"))
+
+	err = repo.DeleteProduct(t.Context(), "PROD009")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+}
+
+func TestVariantsRepositoryLifecycle(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewVariantsRepository(db)
+
+	variants, err := repo.ListVariants(t.Context(), "PROD001")
+	require.NoError(t, err)
+	assert.Len(t, variants, 3)
+
+	_, err = repo.ListVariants(t.Context(), "MISSING")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	price := decimal.RequireFromString("12.50")
+	created, err := repo.CreateVariant(t.Context(), "PROD001", VariantInput{SKU: "SKU001D", Name: "Variant D", Price: &price})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	_, err = repo.CreateVariant(t.Context(), "PROD002", VariantInput{SKU: "SKU001D", Name: "Duplicate"})
+	assert.True(t, errors.Is(err, ErrVariantSKUAlreadyExists))
+
+	updated, err := repo.UpdateVariant(t.Context(), "PROD001", "SKU001D", VariantUpdate{SetPrice: true})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Price)
+
+	_, err = repo.UpdateVariant(t.Context(), "PROD002", "SKU001D", VariantUpdate{SetPrice: true})
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+
+	require.NoError(t, repo.DeleteVariant(t.Context(), "PROD001", "SKU001D"))
+	assert.True(t, errors.Is(repo.DeleteVariant(t.Context(), "PROD001", "SKU001D"), ErrVariantNotFound))
+}
+
+func TestCategoriesRepositoryUpdateAndDelete(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewCategoriesRepository(db)
+
+	category, err := repo.GetCategoryByCode(t.Context(), "SHOES")
+	require.NoError(t, err)
+	assert.Equal(t, "Shoes", category.Name)
+
+	_, err = repo.GetCategoryByCode(t.Context(), "MISSING")
+	assert.True(t, errors.Is(err, ErrCategoryNotFound))
+
+	updated, err := repo.UpdateCategory(t.Context(), "SHOES", CategoryInput{Code: "FOOTWEAR", Name: "Footwear"})
+	require.NoError(t, err)
+	assert.Equal(t, "FOOTWEAR", updated.Code)
+
+	_, err = repo.UpdateCategory(t.Context(), "FOOTWEAR", CategoryInput{Code: "CLOTHING", Name: "Clothing"})
+	assert.True(t, errors.Is(err, ErrCategoryCodeAlreadyExists))
+
+	err = repo.DeleteCategory(t.Context(), "FOOTWEAR", "")
+	var inUse *CategoryInUseError
+	require.True(t, errors.As(err, &inUse))
+	assert.Equal(t, int64(2), inUse.ProductCount)
+	assert.True(t, errors.Is(err, ErrCategoryInUse))
+
+	assert.True(t, errors.Is(repo.DeleteCategory(t.Context(), "FOOTWEAR", "MISSING"), ErrReassignCategoryNotFound))
+
+	require.NoError(t, repo.DeleteCategory(t.Context(), "FOOTWEAR", "ACCESSORIES"))
+	assert.True(t, errors.Is(repo.DeleteCategory(t.Context(), "FOOTWEAR", ""), ErrCategoryNotFound))
+}
+
+func TestCategoriesRepositoryHierarchy(t *testing.T) {
+	db := setupDBWithSeed(t)
+	categories := NewCategoriesRepository(db)
+	products := NewProductsRepository(db)
+
+	dresses, err := categories.CreateCategory(t.Context(), CategoryInput{Code: "DRESSES", Name: "Dresses", ParentCode: "CLOTHING"})
+	require.NoError(t, err)
+	require.NotNil(t, dresses.Parent)
+	assert.Equal(t, "CLOTHING", dresses.Parent.Code)
+
+	_, err = categories.CreateCategory(t.Context(), CategoryInput{Code: "MAXI", Name: "Maxi", ParentCode: "DRESSES"})
+	require.NoError(t, err)
+
+	_, err = categories.CreateCategory(t.Context(), CategoryInput{Code: "MINI", Name: "Mini", ParentCode: "MISSING"})
+	assert.True(t, errors.Is(err, ErrParentCategoryNotFound))
+
+	_, err = categories.UpdateCategory(t.Context(), "CLOTHING", CategoryInput{Code: "CLOTHING", Name: "Clothing", ParentCode: "MAXI"})
+	assert.True(t, errors.Is(err, ErrCategoryCycle))
+
+	_, err = products.CreateProduct(t.Context(), ProductInput{Code: "PROD009", Price: decimal.NewFromInt(30), CategoryCode: "MAXI"})
+	require.NoError(t, err)
+
+	list, total, err := products.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10, Categories: []string{"clothing"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Len(t, list, 4)
+
+	_, total, err = products.ListProducts(t.Context(), ProductCatalogFilter{Offset: 0, Limit: 10, Categories: []string{"Dresses"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	assert.True(t, errors.Is(categories.DeleteCategory(t.Context(), "DRESSES", ""), ErrCategoryHasChildren))
+}
+
+func TestExchangeRatesRepositoryLifecycle(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewExchangeRatesRepository(db)
+
+	rates, err := repo.ListExchangeRates(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	rate, err := repo.GetExchangeRate(t.Context(), "EUR", "GBP")
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.85").Equal(rate))
+
+	rate, err = repo.GetExchangeRate(t.Context(), "GBP", "EUR")
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Div(decimal.RequireFromString("0.85")).Equal(rate))
+
+	rate, err = repo.GetExchangeRate(t.Context(), "USD", "USD")
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(rate))
+
+	_, err = repo.GetExchangeRate(t.Context(), "GBP", "USD")
+	assert.True(t, errors.Is(err, ErrUnsupportedConversion))
+	assert.True(t, errors.Is(err, ErrExchangeRateNotFound))
+
+	_, err = repo.UpsertExchangeRate(t.Context(), ExchangeRate{BaseCurrency: "GBP", QuoteCurrency: "USD", Rate: decimal.RequireFromString("1.27")})
+	require.NoError(t, err)
+	_, err = repo.UpsertExchangeRate(t.Context(), ExchangeRate{BaseCurrency: "GBP", QuoteCurrency: "USD", Rate: decimal.RequireFromString("1.25")})
+	require.NoError(t, err)
+
+	rate, err = repo.GetExchangeRate(t.Context(), "GBP", "USD")
+	require.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("1.25").Equal(rate))
+
+	require.NoError(t, repo.DeleteExchangeRate(t.Context(), "GBP", "USD"))
+	assert.True(t, errors.Is(repo.DeleteExchangeRate(t.Context(), "GBP", "USD"), ErrExchangeRateNotFound))
+
+	product, err := NewProductsRepository(db).CreateProduct(t.Context(), ProductInput{Code: "PROD009", Price: decimal.NewFromInt(5), CategoryCode: "SHOES"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCurrency, product.Currency)
+}
+
+func TestPricesRepositoryGetPriceBook(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewPricesRepository(db)
+	products := NewProductsRepository(db)
+
+	var catalog []Product
+	for _, code := range []string{"PROD001", "PROD002", "PROD003"} {
+		product, err := products.GetProductByCode(t.Context(), code)
+		require.NoError(t, err)
+		catalog = append(catalog, *product)
+	}
+	ids := []uint{catalog[0].ID, catalog[1].ID, catalog[2].ID}
+	duringSale := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	book, err := repo.GetPriceBook(t.Context(), "UK", duringSale, ids)
+	require.NoError(t, err)
+
+	prod001 := book.Resolve(catalog[0])
+	assert.Equal(t, "GBP", prod001.Currency)
+	assert.True(t, decimal.RequireFromString("9.49").Equal(prod001.Price.Original))
+	require.NotNil(t, prod001.Price.Sale)
+	assert.True(t, decimal.RequireFromString("7.99").Equal(*prod001.Price.Sale))
+	require.Len(t, prod001.Variants, 3)
+	assert.True(t, decimal.RequireFromString("10.49").Equal(prod001.Variants[0].Active()))
+	assert.Nil(t, prod001.Variants[0].Sale)
+	assert.True(t, decimal.RequireFromString("7.99").Equal(prod001.Variants[1].Active()))
+
+	prod002 := book.Resolve(catalog[1])
+	assert.Equal(t, "GBP", prod002.Currency)
+	assert.True(t, decimal.RequireFromString("10.99").Equal(prod002.Price.Active()))
+	assert.Nil(t, prod002.Price.Sale)
+
+	prod003 := book.Resolve(catalog[2])
+	assert.Equal(t, "EUR", prod003.Currency)
+	assert.True(t, decimal.RequireFromString("8.75").Equal(prod003.Price.Active()))
+
+	book, err = repo.GetPriceBook(t.Context(), "", duringSale, ids)
+	require.NoError(t, err)
+	prod002 = book.Resolve(catalog[1])
+	assert.Equal(t, "EUR", prod002.Currency)
+	assert.True(t, decimal.RequireFromString("12.49").Equal(prod002.Price.Original))
+	require.NotNil(t, prod002.Price.Sale)
+	assert.True(t, decimal.RequireFromString("9.99").Equal(*prod002.Price.Sale))
+
+	book, err = repo.GetPriceBook(t.Context(), "", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), ids)
+	require.NoError(t, err)
+	assert.Nil(t, book.Resolve(catalog[1]).Price.Sale)
+
+	_, err = repo.GetPriceBook(t.Context(), "FR", duringSale, ids)
+	assert.True(t, errors.Is(err, ErrMarketNotFound))
+}
+
+func TestStockRepositoryLifecycle(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewStockRepository(db)
+
+	levels, err := repo.ListStock(t.Context(), "PROD001", "SKU001A")
+	require.NoError(t, err)
+	require.Len(t, levels, 2)
+	assert.Equal(t, "berlin", levels[0].Warehouse)
+	assert.Equal(t, 5, levels[0].Quantity)
+
+	_, err = repo.ListStock(t.Context(), "PROD002", "SKU001A")
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+
+	level, err := repo.AdjustStock(t.Context(), "PROD001", "SKU001A", StockAdjustment{Warehouse: "berlin", Delta: -2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, level.Quantity)
+	assert.Equal(t, 1, level.Version)
+
+	_, err = repo.AdjustStock(t.Context(), "PROD001", "SKU001A", StockAdjustment{Warehouse: "berlin", Delta: -4})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+
+	_, err = repo.AdjustStock(t.Context(), "PROD004", "SKU004C", StockAdjustment{Warehouse: DefaultWarehouse, Delta: -1})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+
+	level, err = repo.AdjustStock(t.Context(), "PROD004", "SKU004C", StockAdjustment{Warehouse: "paris", Delta: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 4, level.Quantity)
+
+	stale := 0
+	_, err = repo.SetStock(t.Context(), "PROD001", "SKU001A", StockInput{Warehouse: "berlin", Quantity: 8, Version: &stale})
+	assert.True(t, errors.Is(err, ErrStockVersionConflict))
+
+	current := 1
+	level, err = repo.SetStock(t.Context(), "PROD001", "SKU001A", StockInput{Warehouse: "berlin", Quantity: 8, Version: &current})
+	require.NoError(t, err)
+	assert.Equal(t, 8, level.Quantity)
+	assert.Equal(t, 2, level.Version)
+
+	level, err = repo.SetStock(t.Context(), "PROD003", "SKU003A", StockInput{Warehouse: "paris", Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, level.Quantity)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, adjustErr := repo.AdjustStock(t.Context(), "PROD008", "SKU008A", StockAdjustment{Warehouse: DefaultWarehouse, Delta: -1})
+			assert.NoError(t, adjustErr)
+		}()
+	}
+	wg.Wait()
+
+	levels, err = repo.ListStock(t.Context(), "PROD008", "SKU008A")
+	require.NoError(t, err)
+	require.Len(t, levels, 1)
+	assert.Zero(t, levels[0].Quantity)
+	assert.Equal(t, 10, levels[0].Version)
+
+	product, err := NewProductsRepository(db).GetProductByCode(t.Context(), "PROD001")
+	require.NoError(t, err)
+	index := slices.IndexFunc(product.Variants, func(variant Variant) bool { return variant.SKU == "SKU001A" })
+	require.GreaterOrEqual(t, index, 0)
+	assert.Equal(t, 18, product.Variants[index].AvailableQuantity())
+}
+
+func TestProductsRepositoryInStockFilter(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	inStock, outOfStock := true, false
+	products, total, err := repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, InStock: &inStock})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	assert.Len(t, products, 6)
+
+	products, total, err = repo.ListProducts(t.Context(), ProductCatalogFilter{Limit: 10, InStock: &outOfStock})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "PROD002", products[0].Code)
+	assert.Equal(t, "PROD006", products[1].Code)
+}
+
+func TestReservationsRepositoryLifecycle(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewReservationsRepository(db)
+	stock := NewStockRepository(db)
+
+	reservation, err := repo.CreateReservation(t.Context(), ReservationInput{
+		SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 4, CartID: "cart-1", TTL: time.Hour,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, reservation.ID)
+	assert.Equal(t, "SKU003A", reservation.Variant.SKU)
+	assert.Equal(t, ReservationHeld, reservation.Status)
+
+	levels, err := stock.ListStock(t.Context(), "PROD003", "SKU003A")
+	require.NoError(t, err)
+	assert.Equal(t, 10, levels[0].Quantity)
+	assert.Equal(t, 4, levels[0].Reserved)
+
+	_, err = repo.CreateReservation(t.Context(), ReservationInput{SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 7, CartID: "cart-2", TTL: time.Hour})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+
+	_, err = stock.AdjustStock(t.Context(), "PROD003", "SKU003A", StockAdjustment{Warehouse: DefaultWarehouse, Delta: -7})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+
+	_, err = repo.CreateReservation(t.Context(), ReservationInput{SKU: "UNKNOWN", Warehouse: DefaultWarehouse, Quantity: 1, CartID: "cart-2", TTL: time.Hour})
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+
+	committed, err := repo.CommitReservation(t.Context(), reservation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ReservationCommitted, committed.Status)
+
+	levels, err = stock.ListStock(t.Context(), "PROD003", "SKU003A")
+	require.NoError(t, err)
+	assert.Equal(t, 6, levels[0].Quantity)
+	assert.Zero(t, levels[0].Reserved)
+
+	_, err = repo.ReleaseReservation(t.Context(), reservation.ID)
+	assert.True(t, errors.Is(err, ErrReservationNotHeld))
+
+	_, err = repo.GetReservation(t.Context(), "missing")
+	assert.True(t, errors.Is(err, ErrReservationNotFound))
+
+	released, err := repo.CreateReservation(t.Context(), ReservationInput{SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 6, CartID: "cart-2", TTL: time.Hour})
+	require.NoError(t, err)
+	_, err = repo.ReleaseReservation(t.Context(), released.ID)
+	require.NoError(t, err)
+
+	expired, err := repo.CreateReservation(t.Context(), ReservationInput{SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 2, CartID: "cart-3", TTL: time.Minute})
+	require.NoError(t, err)
+	_, err = repo.CreateReservation(t.Context(), ReservationInput{SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 1, CartID: "cart-4", TTL: time.Hour})
+	require.NoError(t, err)
+
+	count, err := repo.ReleaseExpiredReservations(t.Context(), time.Now().Add(30*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	expired, err = repo.GetReservation(t.Context(), expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ReservationExpired, expired.Status)
+
+	levels, err = stock.ListStock(t.Context(), "PROD003", "SKU003A")
+	require.NoError(t, err)
+	assert.Equal(t, 6, levels[0].Quantity)
+	assert.Equal(t, 1, levels[0].Reserved)
+
+	late, err := repo.CreateReservation(t.Context(), ReservationInput{SKU: "SKU003A", Warehouse: DefaultWarehouse, Quantity: 1, CartID: "cart-5", TTL: time.Millisecond})
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = repo.CommitReservation(t.Context(), late.ID)
+	assert.True(t, errors.Is(err, ErrReservationExpired))
+
+	late, err = repo.GetReservation(t.Context(), late.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ReservationExpired, late.Status)
+}
+
+func TestImportRepositoryImport(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewImportRepository(db)
+	products := NewProductsRepository(db)
+
+	price := decimal.RequireFromString("139.90")
+	rows := []ImportRow{
+		{Line: 5, Kind: ImportVariant, ProductCode: "PROD100", Variant: VariantInput{SKU: "SKU100A", Name: "Size 42", Price: &price}},
+		{Line: 4, Kind: ImportProduct, Product: ProductInput{Code: "PROD100", Name: "Chelsea Boots", Price: decimal.RequireFromString("129.90"), CategoryCode: "BOOTS"}},
+		{Line: 3, Kind: ImportProduct, Product: ProductInput{Code: "PROD001", Name: "Renamed", Price: decimal.RequireFromString("11.00"), CategoryCode: "CLOTHING"}},
+		{Line: 2, Kind: ImportCategory, Category: CategoryInput{Code: "BOOTS", Name: "Boots", ParentCode: "SHOES"}},
+	}
+
+	result, err := repo.Import(t.Context(), rows, true)
+	require.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Empty(t, result.Errors)
+
+	_, err = products.GetProductByCode(t.Context(), "PROD100")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	result, err = repo.Import(t.Context(), append(rows,
+		ImportRow{Line: 6, Kind: ImportCategory, Category: CategoryInput{Code: "SANDALS", Name: "Sandals", ParentCode: "MISSING"}},
+		ImportRow{Line: 7, Kind: ImportVariant, ProductCode: "PROD100", Variant: VariantInput{SKU: "SKU001A", Name: "Taken"}},
+		ImportRow{Line: 8, Kind: ImportVariant, ProductCode: "MISSING", Variant: VariantInput{SKU: "SKU999A", Name: "Orphan"}},
+	), false)
+	require.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, []ImportRowError{
+		{Line: 6, Message: ErrParentCategoryNotFound.Error()},
+		{Line: 7, Message: ErrVariantSKUOfAnotherProduct.Error()},
+		{Line: 8, Message: "product not found"},
+	}, result.Errors)
+
+	_, err = products.GetProductByCode(t.Context(), "PROD100")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	result, err = repo.Import(t.Context(), rows, false)
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+
+	imported, err := products.GetProductByCode(t.Context(), "PROD100")
+	require.NoError(t, err)
+	assert.Equal(t, "BOOTS", imported.Category.Code)
+	require.Len(t, imported.Variants, 1)
+	assert.Equal(t, "SKU100A", imported.Variants[0].SKU)
+
+	updated, err := products.GetProductByCode(t.Context(), "PROD001")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name)
+
+	result, err = repo.Import(t.Context(), rows, false)
+	require.NoError(t, err)
+	assert.Zero(t, result.Created)
+	assert.Equal(t, 4, result.Updated)
+}
+
+func TestProductsRepositoryExportProducts(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	var exported []Product
+	err := repo.ExportProducts(t.Context(), ProductCatalogFilter{Limit: 1}, func(product Product) error {
+		exported = append(exported, product)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 8)
+	assert.Equal(t, "PROD001", exported[0].Code)
+	assert.Equal(t, "CLOTHING", exported[0].Category.Code)
+	assert.Len(t, exported[0].Variants, 3)
+
+	var codes []string
+	err = repo.ExportProducts(t.Context(), ProductCatalogFilter{Categories: []string{"Shoes"}, SortBy: SortByCode, SortDesc: true}, func(product Product) error {
+		assert.Equal(t, "SHOES", product.Category.Code)
+		codes = append(codes, product.Code)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, codes, 2)
+	assert.True(t, sort.SliceIsSorted(codes, func(i, j int) bool { return codes[i] > codes[j] }))
+
+	stop := errors.New("stop")
+	var calls int
+	err = repo.ExportProducts(t.Context(), ProductCatalogFilter{}, func(Product) error {
+		calls++
+		return stop
+	})
+	assert.True(t, errors.Is(err, stop))
+	assert.Equal(t, 1, calls)
+}
+
+func TestProductsRepositoryExportProductsWithOneConnection(t *testing.T) {
+	db := setupDBWithSeed(t)
+	repo := NewProductsRepository(db)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	var exported []Product
+	err = repo.ExportProducts(ctx, ProductCatalogFilter{}, func(product Product) error {
+		exported = append(exported, product)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 8)
+	assert.Len(t, exported[0].Variants, 3)
+}
+
+func TestMigrationsRollBackAndReapply(t *testing.T) {
+	db := setupDBWithSeed(t)
+
+	migrations, err := migrate.LoadDir("../sql/migrations")
+	require.NoError(t, err)
+	migrator := migrate.New(db, migrations)
+
+	applied, err := migrator.Up(0)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	rolledBack, err := migrator.Down(len(migrations) + 1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, len(migrations))
+	assert.Equal(t, migrations[len(migrations)-1].Version, rolledBack[0].Version)
+	assert.False(t, db.Migrator().HasTable("products"))
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, len(migrations))
+	assert.False(t, statuses[0].Applied)
+
+	applied, err = migrator.Up(3)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	require.NoError(t, db.Exec("INSERT INTO products (code, price, created_at) VALUES ('PROD001', 10.99, NULL)").Error)
+
+	applied, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations)-3)
+	assert.True(t, db.Migrator().HasTable("stock_reservations"))
+
+	var category string
+	require.NoError(t, db.Raw("SELECT categories.code FROM products JOIN categories ON categories.id = products.category_id WHERE products.code = 'PROD001'").Scan(&category).Error)
+	assert.Equal(t, "UNCATEGORIZED", category)
+
+	var missingCreatedAt int64
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM products WHERE created_at IS NULL").Scan(&missingCreatedAt).Error)
+	assert.Zero(t, missingCreatedAt)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, status.Name)
+		assert.False(t, status.Missing, status.Name)
+	}
+
+	_, err = migrate.New(db, migrations[:1]).Down(1)
+	assert.True(t, errors.Is(err, migrate.ErrMissingMigration))
 }
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ReservationStatus is the lifecycle state of a stock reservation.
+type ReservationStatus string
+
+const (
+	// ReservationHeld is an open reservation holding stock until it expires.
+	ReservationHeld ReservationStatus = "held"
+	// ReservationCommitted is a reservation whose quantity was sold and removed from stock.
+	ReservationCommitted ReservationStatus = "committed"
+	// ReservationReleased is a reservation whose quantity was given back before it expired.
+	ReservationReleased ReservationStatus = "released"
+	// ReservationExpired is a reservation whose quantity was given back after it expired.
+	ReservationExpired ReservationStatus = "expired"
+)
+
+// StockReservation holds a quantity of a variant in a warehouse for a cart until ExpiresAt.
+type StockReservation struct {
+	ID        string            `gorm:"primaryKey;size:36;default:gen_random_uuid()::text"`
+	VariantID uint              `gorm:"not null"`
+	Variant   Variant           `gorm:"foreignKey:VariantID"`
+	Warehouse string            `gorm:"size:32;not null"`
+	Quantity  int               `gorm:"not null"`
+	CartID    string            `gorm:"size:64;not null"`
+	Status    ReservationStatus `gorm:"size:16;not null"`
+	ExpiresAt time.Time         `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName returns the database table name for StockReservation.
+func (r *StockReservation) TableName() string {
+	return "stock_reservations"
+}
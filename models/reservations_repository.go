@@ -0,0 +1,255 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrReservationNotFound indicates that no reservation matches the given id.
+	ErrReservationNotFound = errors.New("reservation not found")
+
+	// ErrReservationNotHeld indicates that a reservation was already committed, released or expired.
+	ErrReservationNotHeld = errors.New("reservation is not held")
+
+	// ErrReservationExpired indicates that a reservation expired before it was committed.
+	ErrReservationExpired = errors.New("reservation expired")
+)
+
+// ReservationsRepository provides persistence operations for stock reservations.
+type ReservationsRepository struct {
+	db *gorm.DB
+}
+
+// ReservationInput defines a new reservation of a variant quantity for a cart.
+type ReservationInput struct {
+	SKU       string
+	Warehouse string
+	Quantity  int
+	CartID    string
+	TTL       time.Duration
+}
+
+// NewReservationsRepository creates a reservations repository backed by gorm.
+func NewReservationsRepository(db *gorm.DB) *ReservationsRepository {
+	return &ReservationsRepository{db: db}
+}
+
+// CreateReservation holds a quantity of the variant with the given SKU until the TTL elapses.
+// The stock level is locked while the available quantity is checked, so concurrent reservations
+// never hold more than is in stock. A reservation exceeding the available quantity fails with ErrInsufficientStock.
+func (r *ReservationsRepository) CreateReservation(ctx context.Context, input ReservationInput) (*StockReservation, error) {
+	ctx = withQueryMethod(ctx, "ReservationsRepository.CreateReservation")
+
+	var reservation StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var variant Variant
+		if err := tx.Where("sku = ?", input.SKU).First(&variant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVariantNotFound
+			}
+
+			return fmt.Errorf("find variant failed: %w", err)
+		}
+
+		level, err := lockStockLevel(tx, variant.ID, input.Warehouse)
+		if err != nil {
+			return err
+		}
+
+		if level.Available() < input.Quantity {
+			return ErrInsufficientStock
+		}
+
+		if err := updateStockLevel(tx, level, level.Quantity, level.Reserved+input.Quantity); err != nil {
+			return err
+		}
+
+		reservation = StockReservation{
+			VariantID: variant.ID,
+			Warehouse: input.Warehouse,
+			Quantity:  input.Quantity,
+			CartID:    input.CartID,
+			Status:    ReservationHeld,
+			ExpiresAt: time.Now().Add(input.TTL),
+		}
+		if err := tx.Omit(clause.Associations).Create(&reservation).Error; err != nil {
+			return fmt.Errorf("create reservation failed: %w", err)
+		}
+
+		return loadReservation(tx, &reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// GetReservation returns a reservation by id with its variant preloaded.
+func (r *ReservationsRepository) GetReservation(ctx context.Context, id string) (*StockReservation, error) {
+	ctx = withQueryMethod(ctx, "ReservationsRepository.GetReservation")
+
+	var reservation StockReservation
+	if err := r.db.WithContext(ctx).Preload("Variant").Where("id = ?", id).First(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+
+		return nil, fmt.Errorf("get reservation failed: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// CommitReservation removes the reserved quantity from stock and marks the reservation committed.
+// A held reservation past its expiry is released as expired and fails with ErrReservationExpired.
+func (r *ReservationsRepository) CommitReservation(ctx context.Context, id string) (*StockReservation, error) {
+	ctx = withQueryMethod(ctx, "ReservationsRepository.CommitReservation")
+
+	var reservation *StockReservation
+	var expired bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		held, err := lockHeldReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		reservation = held
+
+		level, err := lockStockLevel(tx, reservation.VariantID, reservation.Warehouse)
+		if err != nil {
+			return err
+		}
+
+		if !reservation.ExpiresAt.After(time.Now()) {
+			expired = true
+			return finishReservation(tx, reservation, level, ReservationExpired)
+		}
+
+		return finishReservation(tx, reservation, level, ReservationCommitted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		return nil, ErrReservationExpired
+	}
+
+	return reservation, nil
+}
+
+// ReleaseReservation gives the reserved quantity back to the available stock.
+func (r *ReservationsRepository) ReleaseReservation(ctx context.Context, id string) (*StockReservation, error) {
+	ctx = withQueryMethod(ctx, "ReservationsRepository.ReleaseReservation")
+
+	var reservation *StockReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		held, err := lockHeldReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		reservation = held
+
+		level, err := lockStockLevel(tx, reservation.VariantID, reservation.Warehouse)
+		if err != nil {
+			return err
+		}
+
+		return finishReservation(tx, reservation, level, ReservationReleased)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReleaseExpiredReservations releases up to limit held reservations that expired at or before now
+// and returns how many were released. Reservations locked by a concurrent commit or release are skipped.
+func (r *ReservationsRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx = withQueryMethod(ctx, "ReservationsRepository.ReleaseExpiredReservations")
+
+	var released int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservations []StockReservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", ReservationHeld, now).
+			Order("expires_at ASC").
+			Limit(limit).
+			Find(&reservations).Error; err != nil {
+			return fmt.Errorf("list expired reservations failed: %w", err)
+		}
+
+		for i := range reservations {
+			level, err := lockStockLevel(tx, reservations[i].VariantID, reservations[i].Warehouse)
+			if err != nil {
+				return err
+			}
+
+			if err := finishReservation(tx, &reservations[i], level, ReservationExpired); err != nil {
+				return err
+			}
+		}
+
+		released = len(reservations)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return released, nil
+}
+
+// lockHeldReservation loads a reservation and locks it until the end of the transaction.
+// It fails with ErrReservationNotHeld when the reservation is no longer held.
+func lockHeldReservation(tx *gorm.DB, id string) (*StockReservation, error) {
+	var reservation StockReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+
+		return nil, fmt.Errorf("lock reservation failed: %w", err)
+	}
+
+	if reservation.Status != ReservationHeld {
+		return nil, ErrReservationNotHeld
+	}
+
+	return &reservation, nil
+}
+
+// finishReservation moves a held reservation to its final status and updates the locked stock level.
+// Committing removes the quantity from stock, any other status makes it available again.
+func finishReservation(tx *gorm.DB, reservation *StockReservation, level *StockLevel, status ReservationStatus) error {
+	quantity := level.Quantity
+	if status == ReservationCommitted {
+		quantity -= reservation.Quantity
+	}
+
+	if err := updateStockLevel(tx, level, quantity, level.Reserved-reservation.Quantity); err != nil {
+		return err
+	}
+
+	if err := tx.Model(reservation).Omit(clause.Associations).Update("status", status).Error; err != nil {
+		return fmt.Errorf("update reservation failed: %w", err)
+	}
+
+	return loadReservation(tx, reservation)
+}
+
+// loadReservation reloads a reservation with its variant preloaded.
+func loadReservation(db *gorm.DB, reservation *StockReservation) error {
+	if err := db.Preload("Variant").Where("id = ?", reservation.ID).First(reservation).Error; err != nil {
+		return fmt.Errorf("load reservation failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// DefaultWarehouse is the warehouse used when stock is not tracked per warehouse.
+const DefaultWarehouse = "default"
+
+// StockLevel is the stock quantity of a variant in a warehouse.
+// Reserved is the part of Quantity held by open reservations.
+// Version is incremented on every change and guards optimistic updates.
+type StockLevel struct {
+	VariantID uint   `gorm:"primaryKey"`
+	Warehouse string `gorm:"primaryKey;size:32"`
+	Quantity  int    `gorm:"not null"`
+	Reserved  int    `gorm:"not null"`
+	Version   int    `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+// Available returns the quantity that is neither sold nor reserved.
+func (s StockLevel) Available() int {
+	return s.Quantity - s.Reserved
+}
+
+// TableName returns the database table name for StockLevel.
+func (s *StockLevel) TableName() string {
+	return "stock_levels"
+}
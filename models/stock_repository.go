@@ -0,0 +1,204 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInsufficientStock indicates that a change would leave less stock than is reserved.
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// ErrStockVersionConflict indicates that a stock level changed since the expected version was read.
+	ErrStockVersionConflict = errors.New("stock version conflict")
+)
+
+// StockRepository provides persistence operations for variant stock levels.
+type StockRepository struct {
+	db *gorm.DB
+}
+
+// StockAdjustment defines a relative change of the stock of a variant in a warehouse.
+type StockAdjustment struct {
+	Warehouse string
+	Delta     int
+}
+
+// StockInput defines the absolute stock of a variant in a warehouse.
+// When Version is set, the update only applies if the stock level is still at that version;
+// version 0 also matches a stock level that does not exist yet.
+type StockInput struct {
+	Warehouse string
+	Quantity  int
+	Version   *int
+}
+
+// NewStockRepository creates a stock repository backed by gorm.
+func NewStockRepository(db *gorm.DB) *StockRepository {
+	return &StockRepository{db: db}
+}
+
+// ListStock returns the stock levels of a variant of the product with the given code ordered by warehouse.
+func (r *StockRepository) ListStock(ctx context.Context, productCode, sku string) ([]StockLevel, error) {
+	ctx = withQueryMethod(ctx, "StockRepository.ListStock")
+
+	db := r.db.WithContext(ctx)
+	variant, err := findProductVariant(db, productCode, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	var levels []StockLevel
+	if err := db.Where("variant_id = ?", variant.ID).Order("warehouse ASC").Find(&levels).Error; err != nil {
+		return nil, fmt.Errorf("list stock failed: %w", err)
+	}
+
+	return levels, nil
+}
+
+// AdjustStock atomically adds delta to the stock of a variant in a warehouse.
+// The stock level row is locked for the duration of the adjustment, so concurrent adjustments
+// are serialized. An adjustment that would leave less than the reserved quantity fails with ErrInsufficientStock.
+func (r *StockRepository) AdjustStock(ctx context.Context, productCode, sku string, adjustment StockAdjustment) (*StockLevel, error) {
+	ctx = withQueryMethod(ctx, "StockRepository.AdjustStock")
+
+	var level StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		variant, err := findProductVariant(tx, productCode, sku)
+		if err != nil {
+			return err
+		}
+
+		level = StockLevel{VariantID: variant.ID, Warehouse: adjustment.Warehouse}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+			return fmt.Errorf("create stock level failed: %w", err)
+		}
+
+		locked, err := lockStockLevel(tx, variant.ID, adjustment.Warehouse)
+		if err != nil {
+			return err
+		}
+		level = *locked
+
+		quantity := level.Quantity + adjustment.Delta
+		if quantity < level.Reserved {
+			return ErrInsufficientStock
+		}
+
+		return updateStockLevel(tx, &level, quantity, level.Reserved)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// SetStock replaces the stock of a variant in a warehouse.
+// With a Version in the input the update is optimistic and fails with ErrStockVersionConflict
+// when another change was made in the meantime. A quantity below the reserved quantity
+// fails with ErrInsufficientStock.
+func (r *StockRepository) SetStock(ctx context.Context, productCode, sku string, input StockInput) (*StockLevel, error) {
+	ctx = withQueryMethod(ctx, "StockRepository.SetStock")
+
+	var level StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		variant, err := findProductVariant(tx, productCode, sku)
+		if err != nil {
+			return err
+		}
+
+		level = StockLevel{VariantID: variant.ID, Warehouse: input.Warehouse}
+		if input.Version == nil || *input.Version == 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+				return fmt.Errorf("create stock level failed: %w", err)
+			}
+		}
+
+		query := tx.Where("variant_id = ? AND warehouse = ?", variant.ID, input.Warehouse)
+		if input.Version != nil {
+			query = query.Where("version = ?", *input.Version)
+		}
+
+		if err := query.First(&level).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStockVersionConflict
+			}
+
+			return fmt.Errorf("find stock level failed: %w", err)
+		}
+
+		if input.Quantity < level.Reserved {
+			return ErrInsufficientStock
+		}
+
+		return updateStockLevel(tx, &level, input.Quantity, level.Reserved)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// lockStockLevel loads the stock level of a variant in a warehouse and locks it until the end of the transaction.
+// A missing stock level fails with ErrInsufficientStock.
+func lockStockLevel(tx *gorm.DB, variantID uint, warehouse string) (*StockLevel, error) {
+	var level StockLevel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("variant_id = ? AND warehouse = ?", variantID, warehouse).
+		First(&level).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInsufficientStock
+		}
+
+		return nil, fmt.Errorf("lock stock level failed: %w", err)
+	}
+
+	return &level, nil
+}
+
+// updateStockLevel sets the quantity and reserved quantity of a stock level and bumps its version.
+// The update is conditional on the version read before, so that a concurrent change
+// makes it fail with ErrStockVersionConflict instead of being overwritten.
+func updateStockLevel(tx *gorm.DB, level *StockLevel, quantity, reserved int) error {
+	result := tx.Model(&StockLevel{}).
+		Where("variant_id = ? AND warehouse = ? AND version = ?", level.VariantID, level.Warehouse, level.Version).
+		Updates(map[string]any{
+			"quantity":   quantity,
+			"reserved":   reserved,
+			"version":    level.Version + 1,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("update stock level failed: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrStockVersionConflict
+	}
+
+	if err := tx.Where("variant_id = ? AND warehouse = ?", level.VariantID, level.Warehouse).First(level).Error; err != nil {
+		return fmt.Errorf("reload stock level failed: %w", err)
+	}
+
+	return nil
+}
+
+// findProductVariant looks up the variant with the given SKU of the product with the given code.
+func findProductVariant(db *gorm.DB, productCode, sku string) (*Variant, error) {
+	var variant Variant
+	if err := productVariantScope(db, productCode, sku).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVariantNotFound
+		}
+
+		return nil, fmt.Errorf("find variant failed: %w", err)
+	}
+
+	return &variant, nil
+}
@@ -6,11 +6,23 @@ import (
 
 // Variant represents a product variant.
 type Variant struct {
-	ID        uint             `gorm:"primaryKey"`
-	ProductID uint             `gorm:"not null"`
-	Name      string           `gorm:"not null"`
-	SKU       string           `gorm:"uniqueIndex;not null"`
-	Price     *decimal.Decimal `gorm:"type:decimal(10,2);null"`
+	ID          uint             `gorm:"primaryKey"`
+	ProductID   uint             `gorm:"not null"`
+	Name        string           `gorm:"not null"`
+	SKU         string           `gorm:"uniqueIndex;not null"`
+	Price       *decimal.Decimal `gorm:"type:decimal(10,2);null"`
+	StockLevels []StockLevel     `gorm:"foreignKey:VariantID"`
+}
+
+// AvailableQuantity returns the unreserved stock of the variant summed over all warehouses.
+// It only counts stock levels that were loaded with the variant.
+func (v *Variant) AvailableQuantity() int {
+	var quantity int
+	for _, level := range v.StockLevels {
+		quantity += level.Available()
+	}
+
+	return quantity
 }
 
 // TableName returns the database table name for Variant.
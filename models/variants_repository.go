@@ -0,0 +1,166 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrVariantSKUAlreadyExists indicates a unique violation for variant SKU.
+	ErrVariantSKUAlreadyExists = errors.New("variant sku already exists")
+
+	// ErrVariantNotFound indicates that no variant matches the given product code and SKU.
+	ErrVariantNotFound = errors.New("variant not found")
+)
+
+// VariantsRepository provides persistence operations for product variants.
+type VariantsRepository struct {
+	db *gorm.DB
+}
+
+// VariantInput defines the writable fields of a new variant.
+// A nil Price means the variant inherits the product price.
+type VariantInput struct {
+	SKU   string
+	Name  string
+	Price *decimal.Decimal
+}
+
+// VariantUpdate defines a partial variant update. Nil fields are left untouched.
+// When SetPrice is true the price override is replaced by Price, and a nil Price clears it.
+type VariantUpdate struct {
+	Name     *string
+	SetPrice bool
+	Price    *decimal.Decimal
+}
+
+// NewVariantsRepository creates a variants repository backed by gorm.
+func NewVariantsRepository(db *gorm.DB) *VariantsRepository {
+	return &VariantsRepository{db: db}
+}
+
+// ListVariants returns the variants of the product with the given code ordered by id.
+func (r *VariantsRepository) ListVariants(ctx context.Context, productCode string) ([]Variant, error) {
+	ctx = withQueryMethod(ctx, "VariantsRepository.ListVariants")
+
+	db := r.db.WithContext(ctx)
+	product, err := findProductByCode(db, productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var variants []Variant
+	if err := db.Where("product_id = ?", product.ID).Order("id ASC").Find(&variants).Error; err != nil {
+		return nil, fmt.Errorf("list variants failed: %w", err)
+	}
+
+	return variants, nil
+}
+
+// CreateVariant persists a new variant for the product with the given code.
+func (r *VariantsRepository) CreateVariant(ctx context.Context, productCode string, input VariantInput) (*Variant, error) {
+	ctx = withQueryMethod(ctx, "VariantsRepository.CreateVariant")
+
+	var variant Variant
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		product, err := findProductByCode(tx, productCode)
+		if err != nil {
+			return err
+		}
+
+		variant = Variant{ProductID: product.ID, SKU: input.SKU, Name: input.Name, Price: input.Price}
+		if err := tx.Omit(clause.Associations).Create(&variant).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrVariantSKUAlreadyExists
+			}
+
+			return fmt.Errorf("create variant failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// UpdateVariant applies a partial update to a variant of the product with the given code.
+func (r *VariantsRepository) UpdateVariant(ctx context.Context, productCode, sku string, update VariantUpdate) (*Variant, error) {
+	ctx = withQueryMethod(ctx, "VariantsRepository.UpdateVariant")
+
+	var variant Variant
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := productVariantScope(tx, productCode, sku).First(&variant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVariantNotFound
+			}
+
+			return fmt.Errorf("update variant failed: %w", err)
+		}
+
+		changes := map[string]any{}
+		if update.Name != nil {
+			changes["name"] = *update.Name
+		}
+
+		if update.SetPrice {
+			if update.Price != nil {
+				changes["price"] = *update.Price
+			} else {
+				changes["price"] = nil
+			}
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&variant).Updates(changes).Error; err != nil {
+			return fmt.Errorf("update variant failed: %w", err)
+		}
+
+		if err := tx.First(&variant, variant.ID).Error; err != nil {
+			return fmt.Errorf("reload variant failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// DeleteVariant removes a variant of the product with the given code.
+func (r *VariantsRepository) DeleteVariant(ctx context.Context, productCode, sku string) error {
+	ctx = withQueryMethod(ctx, "VariantsRepository.DeleteVariant")
+
+	db := r.db.WithContext(ctx)
+	result := db.
+		Where("sku = ? AND product_id IN (?)", sku, db.Model(&Product{}).Select("id").Where("code = ?", productCode)).
+		Delete(&Variant{})
+	if result.Error != nil {
+		return fmt.Errorf("delete variant failed: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrVariantNotFound
+	}
+
+	return nil
+}
+
+// productVariantScope restricts a query to the variant with the given SKU of the given product.
+func productVariantScope(db *gorm.DB, productCode, sku string) *gorm.DB {
+	return db.
+		Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("products.code = ? AND product_variants.sku = ?", productCode, sku)
+}
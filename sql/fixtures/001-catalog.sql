@@ -1,13 +1,18 @@
+INSERT INTO categories (code, name) VALUES
+('CLOTHING', 'Clothing'),
+('SHOES', 'Shoes'),
+('ACCESSORIES', 'Accessories');
+
 -- Insert 8 products
-INSERT INTO products (code, price) VALUES
-('PROD001', 10.99),
-('PROD002', 12.49),
-('PROD003', 8.75),
-('PROD004', 15.00),
-('PROD005', 22.99),
-('PROD006', 5.50),
-('PROD007', 18.20),
-('PROD008', 9.99);
+INSERT INTO products (code, name, description, price, category_id) VALUES
+('PROD001', 'Linen Summer Shirt', 'Lightweight linen shirt with a relaxed fit for warm days.', 10.99, (SELECT id FROM categories WHERE code = 'CLOTHING')),
+('PROD002', 'Leather Ankle Boots', 'Polished leather boots with a low block heel.', 12.49, (SELECT id FROM categories WHERE code = 'SHOES')),
+('PROD003', 'Silk Print Scarf', 'Square silk scarf with a hand-rolled edge.', 8.75, (SELECT id FROM categories WHERE code = 'ACCESSORIES')),
+('PROD004', 'Wool Midi Dress', 'Fitted wool dress with long sleeves and a midi hem.', 15.00, (SELECT id FROM categories WHERE code = 'CLOTHING')),
+('PROD005', 'Canvas Tote Bag', 'Sturdy canvas tote with leather handles.', 22.99, (SELECT id FROM categories WHERE code = 'ACCESSORIES')),
+('PROD006', 'Suede Loafers', 'Soft suede loafers with a cushioned insole.', 5.50, (SELECT id FROM categories WHERE code = 'SHOES')),
+('PROD007', 'Cotton Maxi Skirt', 'Flowing cotton skirt with an elastic waist.', 18.20, (SELECT id FROM categories WHERE code = 'CLOTHING')),
+('PROD008', 'Leather Card Holder', 'Slim leather card holder with four slots.', 9.99, (SELECT id FROM categories WHERE code = 'ACCESSORIES'));
 
 -- Insert variants for each product using product code to look up product_id
 
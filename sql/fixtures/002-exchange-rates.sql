@@ -0,0 +1,4 @@
+INSERT INTO exchange_rates (base_currency, quote_currency, rate) VALUES
+('EUR', 'GBP', 0.85000000),
+('EUR', 'USD', 1.08000000)
+ON CONFLICT (base_currency, quote_currency) DO NOTHING;
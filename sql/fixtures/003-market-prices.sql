@@ -0,0 +1,20 @@
+INSERT INTO markets (code, name, currency) VALUES
+('DE', 'Germany', 'EUR'),
+('UK', 'United Kingdom', 'GBP'),
+('US', 'United States', 'USD')
+ON CONFLICT (code) DO NOTHING;
+
+-- UK and US price lists; DE sells at the stored EUR prices
+INSERT INTO market_prices (product_id, variant_id, market_code, price) VALUES
+((SELECT id FROM products WHERE code = 'PROD001'), NULL, 'UK', 9.49),
+((SELECT id FROM products WHERE code = 'PROD001'), (SELECT id FROM product_variants WHERE sku = 'SKU001A'), 'UK', 10.49),
+((SELECT id FROM products WHERE code = 'PROD002'), NULL, 'UK', 10.99),
+((SELECT id FROM products WHERE code = 'PROD004'), NULL, 'UK', 12.99),
+((SELECT id FROM products WHERE code = 'PROD001'), NULL, 'US', 11.99),
+((SELECT id FROM products WHERE code = 'PROD002'), NULL, 'US', 13.49),
+((SELECT id FROM products WHERE code = 'PROD005'), NULL, 'US', 24.99);
+
+-- Sales: PROD002 on the stored prices, PROD001 on the UK price list
+INSERT INTO sale_prices (product_id, variant_id, market_code, price, starts_at, ends_at) VALUES
+((SELECT id FROM products WHERE code = 'PROD002'), NULL, NULL, 9.99, '2025-01-01T00:00:00Z', '2030-01-01T00:00:00Z'),
+((SELECT id FROM products WHERE code = 'PROD001'), NULL, 'UK', 7.99, '2025-01-01T00:00:00Z', '2030-01-01T00:00:00Z');
@@ -0,0 +1,10 @@
+-- Every variant is stocked in the default warehouse except the sold-out PROD002 and SKU004C
+INSERT INTO stock_levels (variant_id, warehouse, quantity)
+SELECT id, 'default', 10 FROM product_variants
+WHERE sku NOT IN ('SKU002A', 'SKU002B', 'SKU004C')
+ON CONFLICT (variant_id, warehouse) DO NOTHING;
+
+INSERT INTO stock_levels (variant_id, warehouse, quantity) VALUES
+((SELECT id FROM product_variants WHERE sku = 'SKU002A'), 'default', 0),
+((SELECT id FROM product_variants WHERE sku = 'SKU001A'), 'berlin', 5)
+ON CONFLICT (variant_id, warehouse) DO NOTHING;
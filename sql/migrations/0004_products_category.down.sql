@@ -0,0 +1,3 @@
+ALTER TABLE products
+DROP CONSTRAINT IF EXISTS fk_products_category,
+DROP COLUMN IF EXISTS category_id;
@@ -0,0 +1,31 @@
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS category_id INTEGER;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (
+		SELECT 1
+		FROM pg_constraint
+		WHERE conname = 'fk_products_category'
+	) THEN
+		ALTER TABLE products
+		ADD CONSTRAINT fk_products_category
+		FOREIGN KEY (category_id)
+		REFERENCES categories(id)
+		ON DELETE RESTRICT;
+	END IF;
+END $$;
+
+-- Products created before categories existed are assigned to a fallback category,
+-- which is only created when there are such products.
+INSERT INTO categories (code, name)
+SELECT 'UNCATEGORIZED', 'Uncategorized'
+WHERE EXISTS (SELECT 1 FROM products WHERE category_id IS NULL)
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE products
+SET category_id = (SELECT id FROM categories WHERE code = 'UNCATEGORIZED')
+WHERE category_id IS NULL;
+
+ALTER TABLE products
+ALTER COLUMN category_id SET NOT NULL;
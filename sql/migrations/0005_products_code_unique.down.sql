@@ -0,0 +1,3 @@
+ALTER TABLE products
+DROP CONSTRAINT IF EXISTS uq_products_code,
+ALTER COLUMN code DROP NOT NULL;
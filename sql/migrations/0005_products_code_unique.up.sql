@@ -0,0 +1,15 @@
+ALTER TABLE products
+ALTER COLUMN code SET NOT NULL;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (
+		SELECT 1
+		FROM pg_constraint
+		WHERE conname = 'uq_products_code'
+	) THEN
+		ALTER TABLE products
+		ADD CONSTRAINT uq_products_code
+		UNIQUE (code);
+	END IF;
+END $$;
@@ -0,0 +1,19 @@
+ALTER TABLE categories
+ADD COLUMN IF NOT EXISTS parent_id INTEGER;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (
+		SELECT 1
+		FROM pg_constraint
+		WHERE conname = 'fk_categories_parent'
+	) THEN
+		ALTER TABLE categories
+		ADD CONSTRAINT fk_categories_parent
+		FOREIGN KEY (parent_id)
+		REFERENCES categories(id)
+		ON DELETE RESTRICT;
+	END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
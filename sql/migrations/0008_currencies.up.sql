@@ -0,0 +1,10 @@
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'EUR';
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (base_currency, quote_currency)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sale_prices;
+DROP TABLE IF EXISTS market_prices;
+DROP TABLE IF EXISTS markets;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS markets (
+    code VARCHAR(2) PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    currency CHAR(3) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS market_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    market_code VARCHAR(2) NOT NULL REFERENCES markets(code) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS market_prices_product_market_idx
+    ON market_prices (product_id, market_code) WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS market_prices_variant_market_idx
+    ON market_prices (variant_id, market_code) WHERE variant_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS sale_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    market_code VARCHAR(2) REFERENCES markets(code) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS sale_prices_product_window_idx
+    ON sale_prices (product_id, starts_at, ends_at);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS stock_levels (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    warehouse VARCHAR(32) NOT NULL DEFAULT 'default',
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    version INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (variant_id, warehouse)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS stock_reservations;
+
+ALTER TABLE stock_levels
+DROP CONSTRAINT IF EXISTS ck_stock_levels_reserved,
+DROP COLUMN IF EXISTS reserved;
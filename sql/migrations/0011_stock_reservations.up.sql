@@ -0,0 +1,33 @@
+ALTER TABLE stock_levels
+ADD COLUMN IF NOT EXISTS reserved INTEGER NOT NULL DEFAULT 0;
+
+DO $$
+BEGIN
+	IF NOT EXISTS (
+		SELECT 1
+		FROM pg_constraint
+		WHERE conname = 'ck_stock_levels_reserved'
+	) THEN
+		ALTER TABLE stock_levels
+		ADD CONSTRAINT ck_stock_levels_reserved
+		CHECK (reserved >= 0 AND reserved <= quantity);
+	END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id VARCHAR(36) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    variant_id INTEGER NOT NULL,
+    warehouse VARCHAR(32) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    cart_id VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'held',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (variant_id, warehouse) REFERENCES stock_levels(variant_id, warehouse) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_held_expiry_idx
+    ON stock_reservations (expires_at) WHERE status = 'held';
+CREATE INDEX IF NOT EXISTS stock_reservations_cart_idx
+    ON stock_reservations (cart_id);
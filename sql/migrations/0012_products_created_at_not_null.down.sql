@@ -0,0 +1,2 @@
+ALTER TABLE products
+ALTER COLUMN created_at DROP NOT NULL;
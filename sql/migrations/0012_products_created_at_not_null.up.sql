@@ -0,0 +1,6 @@
+UPDATE products
+SET created_at = COALESCE(updated_at, NOW())
+WHERE created_at IS NULL;
+
+ALTER TABLE products
+ALTER COLUMN created_at SET NOT NULL;